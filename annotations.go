@@ -0,0 +1,275 @@
+package backstage
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Well-known annotations, with meaning defined by Backstage.
+// https://backstage.io/docs/features/software-catalog/well-known-annotations
+const (
+	// AnnotationManagedByLocation is set by the catalog to the location the entity was read from, e.g. "url:https://...".
+	AnnotationManagedByLocation = "backstage.io/managed-by-location"
+
+	// AnnotationManagedByOriginLocation is set by the catalog to the location that was originally registered, and led to the
+	// entity being read.
+	AnnotationManagedByOriginLocation = "backstage.io/managed-by-origin-location"
+
+	// AnnotationOrphan is set by the catalog to "true" on entities that are no longer referenced by any location.
+	AnnotationOrphan = "backstage.io/orphan"
+
+	// AnnotationSourceLocation points to the source code of the entity, e.g. "url:https://github.com/org/repo/tree/main/".
+	AnnotationSourceLocation = "backstage.io/source-location"
+
+	// AnnotationTechDocsRef points to the documentation of the entity, e.g. "dir:." relative to the catalog file.
+	AnnotationTechDocsRef = "backstage.io/techdocs-ref"
+
+	// AnnotationViewURL is a URL where the catalog file of the entity can be viewed.
+	AnnotationViewURL = "backstage.io/view-url"
+
+	// AnnotationEditURL is a URL where the catalog file of the entity can be edited.
+	AnnotationEditURL = "backstage.io/edit-url"
+
+	// AnnotationGitHubProjectSlug identifies the GitHub repository of the entity, in "owner/repo" form.
+	AnnotationGitHubProjectSlug = "github.com/project-slug"
+
+	// AnnotationKubernetesID identifies the Kubernetes resources of the entity, matched by the "backstage.io/kubernetes-id" label.
+	AnnotationKubernetesID = "backstage.io/kubernetes-id"
+)
+
+// ProjectSlug identifies a repository by its owner and name, as in the "github.com/project-slug" annotation.
+type ProjectSlug struct {
+	// Owner of the repository, i.e. a user or an organization.
+	Owner string
+
+	// Repo is the name of the repository.
+	Repo string
+}
+
+// String returns the slug in "owner/repo" form.
+func (s ProjectSlug) String() string {
+	return s.Owner + "/" + s.Repo
+}
+
+// ParseProjectSlug parses a slug in "owner/repo" form.
+func ParseProjectSlug(slug string) (ProjectSlug, error) {
+	owner, repo, ok := strings.Cut(slug, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") || strings.TrimSpace(slug) != slug {
+		return ProjectSlug{}, fmt.Errorf("invalid project slug %q: expected owner/repo", slug)
+	}
+
+	return ProjectSlug{Owner: owner, Repo: repo}, nil
+}
+
+// ParseLocationRef parses a location reference in "<type>:<target>" form, as used by annotations pointing to locations,
+// e.g. "url:https://github.com/org/repo/blob/main/catalog-info.yaml".
+func ParseLocationRef(ref string) (LocationSpec, error) {
+	t, target, ok := strings.Cut(ref, ":")
+	if !ok || t == "" || target == "" || strings.ContainsAny(t, " /") {
+		return LocationSpec{}, fmt.Errorf("invalid location ref %q: expected <type>:<target>", ref)
+	}
+
+	return LocationSpec{Type: t, Target: target}, nil
+}
+
+// ManagedByLocation returns the parsed "backstage.io/managed-by-location" annotation, or nil if it is not set.
+func (m *EntityMeta) ManagedByLocation() (*LocationSpec, error) {
+	return m.locationAnnotation(AnnotationManagedByLocation)
+}
+
+// SetManagedByLocation sets the "backstage.io/managed-by-location" annotation.
+func (m *EntityMeta) SetManagedByLocation(location LocationSpec) error {
+	return m.setLocationAnnotation(AnnotationManagedByLocation, location)
+}
+
+// ManagedByOriginLocation returns the parsed "backstage.io/managed-by-origin-location" annotation, or nil if it is not set.
+func (m *EntityMeta) ManagedByOriginLocation() (*LocationSpec, error) {
+	return m.locationAnnotation(AnnotationManagedByOriginLocation)
+}
+
+// SetManagedByOriginLocation sets the "backstage.io/managed-by-origin-location" annotation.
+func (m *EntityMeta) SetManagedByOriginLocation(location LocationSpec) error {
+	return m.setLocationAnnotation(AnnotationManagedByOriginLocation, location)
+}
+
+// SourceLocation returns the parsed "backstage.io/source-location" annotation, or nil if it is not set.
+func (m *EntityMeta) SourceLocation() (*LocationSpec, error) {
+	return m.locationAnnotation(AnnotationSourceLocation)
+}
+
+// SetSourceLocation sets the "backstage.io/source-location" annotation.
+func (m *EntityMeta) SetSourceLocation(location LocationSpec) error {
+	return m.setLocationAnnotation(AnnotationSourceLocation, location)
+}
+
+// TechDocsRef returns the parsed "backstage.io/techdocs-ref" annotation, or nil if it is not set.
+func (m *EntityMeta) TechDocsRef() (*LocationSpec, error) {
+	return m.locationAnnotation(AnnotationTechDocsRef)
+}
+
+// SetTechDocsRef sets the "backstage.io/techdocs-ref" annotation.
+func (m *EntityMeta) SetTechDocsRef(location LocationSpec) error {
+	return m.setLocationAnnotation(AnnotationTechDocsRef, location)
+}
+
+// ViewURL returns the parsed "backstage.io/view-url" annotation, or nil if it is not set.
+func (m *EntityMeta) ViewURL() (*url.URL, error) {
+	return m.urlAnnotation(AnnotationViewURL)
+}
+
+// SetViewURL sets the "backstage.io/view-url" annotation. The URL must be absolute.
+func (m *EntityMeta) SetViewURL(u string) error {
+	return m.setURLAnnotation(AnnotationViewURL, u)
+}
+
+// EditURL returns the parsed "backstage.io/edit-url" annotation, or nil if it is not set.
+func (m *EntityMeta) EditURL() (*url.URL, error) {
+	return m.urlAnnotation(AnnotationEditURL)
+}
+
+// SetEditURL sets the "backstage.io/edit-url" annotation. The URL must be absolute.
+func (m *EntityMeta) SetEditURL(u string) error {
+	return m.setURLAnnotation(AnnotationEditURL, u)
+}
+
+// GitHubProjectSlug returns the parsed "github.com/project-slug" annotation, or nil if it is not set.
+func (m *EntityMeta) GitHubProjectSlug() (*ProjectSlug, error) {
+	v, ok := m.Annotations[AnnotationGitHubProjectSlug]
+	if !ok {
+		return nil, nil
+	}
+
+	slug, err := ParseProjectSlug(v)
+	if err != nil {
+		return nil, fmt.Errorf("annotation %s: %w", AnnotationGitHubProjectSlug, err)
+	}
+
+	return &slug, nil
+}
+
+// SetGitHubProjectSlug sets the "github.com/project-slug" annotation.
+func (m *EntityMeta) SetGitHubProjectSlug(slug ProjectSlug) error {
+	if _, err := ParseProjectSlug(slug.String()); err != nil {
+		return fmt.Errorf("annotation %s: %w", AnnotationGitHubProjectSlug, err)
+	}
+
+	m.setAnnotation(AnnotationGitHubProjectSlug, slug.String())
+
+	return nil
+}
+
+// KubernetesID returns the "backstage.io/kubernetes-id" annotation, or an empty string if it is not set.
+func (m *EntityMeta) KubernetesID() string {
+	return m.Annotations[AnnotationKubernetesID]
+}
+
+// SetKubernetesID sets the "backstage.io/kubernetes-id" annotation. Since it is matched against a Kubernetes label, the ID
+// must be a valid label value.
+func (m *EntityMeta) SetKubernetesID(id string) error {
+	if !isKubernetesLabelValue(id) {
+		return fmt.Errorf("annotation %s: invalid Kubernetes label value %q", AnnotationKubernetesID, id)
+	}
+
+	m.setAnnotation(AnnotationKubernetesID, id)
+
+	return nil
+}
+
+// IsOrphan returns true if the entity is marked by the catalog as orphaned (see AnnotationOrphan).
+func (m *EntityMeta) IsOrphan() bool {
+	return m.Annotations[AnnotationOrphan] == "true"
+}
+
+// locationAnnotation returns the annotation parsed as a location reference, or nil if it is not set.
+func (m *EntityMeta) locationAnnotation(key string) (*LocationSpec, error) {
+	v, ok := m.Annotations[key]
+	if !ok {
+		return nil, nil
+	}
+
+	location, err := ParseLocationRef(v)
+	if err != nil {
+		return nil, fmt.Errorf("annotation %s: %w", key, err)
+	}
+
+	return &location, nil
+}
+
+// setLocationAnnotation validates the location and sets it as the annotation in "<type>:<target>" form.
+func (m *EntityMeta) setLocationAnnotation(key string, location LocationSpec) error {
+	ref := location.Type + ":" + location.Target
+	if _, err := ParseLocationRef(ref); err != nil {
+		return fmt.Errorf("annotation %s: %w", key, err)
+	}
+
+	m.setAnnotation(key, ref)
+
+	return nil
+}
+
+// urlAnnotation returns the annotation parsed as a URL, or nil if it is not set.
+func (m *EntityMeta) urlAnnotation(key string) (*url.URL, error) {
+	v, ok := m.Annotations[key]
+	if !ok {
+		return nil, nil
+	}
+
+	u, err := parseAbsoluteURL(v)
+	if err != nil {
+		return nil, fmt.Errorf("annotation %s: %w", key, err)
+	}
+
+	return u, nil
+}
+
+// setURLAnnotation validates the URL and sets it as the annotation.
+func (m *EntityMeta) setURLAnnotation(key string, u string) error {
+	if _, err := parseAbsoluteURL(u); err != nil {
+		return fmt.Errorf("annotation %s: %w", key, err)
+	}
+
+	m.setAnnotation(key, u)
+
+	return nil
+}
+
+// setAnnotation sets the annotation, creating the annotations map if needed.
+func (m *EntityMeta) setAnnotation(key string, value string) {
+	if m.Annotations == nil {
+		m.Annotations = map[string]string{}
+	}
+
+	m.Annotations[key] = value
+}
+
+// parseAbsoluteURL parses the URL, requiring it to have a scheme and a host.
+func parseAbsoluteURL(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("URL must be absolute: %q", s)
+	}
+
+	return u, nil
+}
+
+// isKubernetesLabelValue returns true if the value is a valid Kubernetes label value: at most 63 alphanumeric characters,
+// dashes, underscores and dots, starting and ending with an alphanumeric character.
+func isKubernetesLabelValue(v string) bool {
+	if v == "" || len(v) > 63 {
+		return false
+	}
+
+	for i, c := range v {
+		alphanumeric := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+		if !alphanumeric && ((i == 0 || i == len(v)-1) || !strings.ContainsRune("-_.", c)) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,104 @@
+package backstage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEntityMetaAnnotations tests reading of well-known annotations.
+func TestEntityMetaAnnotations(t *testing.T) {
+	m := EntityMeta{
+		Annotations: map[string]string{
+			AnnotationManagedByLocation: "url:https://github.com/org/repo/blob/main/catalog-info.yaml",
+			AnnotationSourceLocation:    "url:https://github.com/org/repo/tree/main/",
+			AnnotationTechDocsRef:       "dir:.",
+			AnnotationViewURL:           "https://github.com/org/repo/blob/main/catalog-info.yaml",
+			AnnotationGitHubProjectSlug: "org/repo",
+			AnnotationKubernetesID:      "repo",
+			AnnotationOrphan:            "true",
+		},
+	}
+
+	location, err := m.ManagedByLocation()
+	assert.NoError(t, err, "ManagedByLocation should not return an error")
+	assert.Equal(t, &LocationSpec{Type: LocationTypeURL, Target: "https://github.com/org/repo/blob/main/catalog-info.yaml"}, location,
+		"ManagedByLocation should return the parsed location")
+
+	docs, err := m.TechDocsRef()
+	assert.NoError(t, err, "TechDocsRef should not return an error")
+	assert.Equal(t, &LocationSpec{Type: "dir", Target: "."}, docs, "TechDocsRef should return the parsed location")
+
+	view, err := m.ViewURL()
+	assert.NoError(t, err, "ViewURL should not return an error")
+	assert.Equal(t, "github.com", view.Host, "ViewURL should return the parsed URL")
+
+	slug, err := m.GitHubProjectSlug()
+	assert.NoError(t, err, "GitHubProjectSlug should not return an error")
+	assert.Equal(t, &ProjectSlug{Owner: "org", Repo: "repo"}, slug, "GitHubProjectSlug should return owner and repo")
+
+	origin, err := m.ManagedByOriginLocation()
+	assert.NoError(t, err, "Missing annotation should not return an error")
+	assert.Nil(t, origin, "Missing annotation should return nil")
+
+	assert.Equal(t, "repo", m.KubernetesID(), "KubernetesID should return the annotation")
+	assert.True(t, m.IsOrphan(), "Entity should be orphaned")
+}
+
+// TestEntityMetaAnnotations_Invalid tests that malformed well-known annotations return errors.
+func TestEntityMetaAnnotations_Invalid(t *testing.T) {
+	m := EntityMeta{
+		Annotations: map[string]string{
+			AnnotationSourceLocation:    "https-without-type",
+			AnnotationEditURL:           "/relative/path",
+			AnnotationGitHubProjectSlug: "org/repo/extra",
+		},
+	}
+
+	_, err := m.SourceLocation()
+	assert.EqualError(t, err, `annotation backstage.io/source-location: invalid location ref "https-without-type": expected <type>:<target>`,
+		"SourceLocation should return an error")
+
+	_, err = m.EditURL()
+	assert.EqualError(t, err, `annotation backstage.io/edit-url: URL must be absolute: "/relative/path"`, "EditURL should return an error")
+
+	_, err = m.GitHubProjectSlug()
+	assert.EqualError(t, err, `annotation github.com/project-slug: invalid project slug "org/repo/extra": expected owner/repo`,
+		"GitHubProjectSlug should return an error")
+}
+
+// TestEntityMetaSetAnnotations tests validation and setting of well-known annotations.
+func TestEntityMetaSetAnnotations(t *testing.T) {
+	var m EntityMeta
+
+	assert.NoError(t, m.SetSourceLocation(LocationSpec{Type: LocationTypeURL, Target: "https://github.com/org/repo/"}),
+		"SetSourceLocation should not return an error")
+	assert.NoError(t, m.SetViewURL("https://github.com/org/repo"), "SetViewURL should not return an error")
+	assert.NoError(t, m.SetGitHubProjectSlug(ProjectSlug{Owner: "org", Repo: "repo"}), "SetGitHubProjectSlug should not return an error")
+	assert.NoError(t, m.SetKubernetesID("repo-api"), "SetKubernetesID should not return an error")
+
+	assert.Equal(t, map[string]string{
+		AnnotationSourceLocation:    "url:https://github.com/org/repo/",
+		AnnotationViewURL:           "https://github.com/org/repo",
+		AnnotationGitHubProjectSlug: "org/repo",
+		AnnotationKubernetesID:      "repo-api",
+	}, m.Annotations, "Setters should set the annotations")
+
+	tests := []struct {
+		name string
+		set  func() error
+	}{
+		{name: "location without type", set: func() error { return m.SetTechDocsRef(LocationSpec{Target: "."}) }},
+		{name: "relative URL", set: func() error { return m.SetEditURL("edit") }},
+		{name: "slug without repo", set: func() error { return m.SetGitHubProjectSlug(ProjectSlug{Owner: "org"}) }},
+		{name: "ID ending with dash", set: func() error { return m.SetKubernetesID("repo-") }},
+		{name: "ID with spaces", set: func() error { return m.SetKubernetesID("my repo") }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Error(t, test.set(), "Setter should return an error for invalid value")
+			assert.Len(t, m.Annotations, 4, "Setter should not set invalid value")
+		})
+	}
+}
@@ -0,0 +1,328 @@
+package backstage
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bindingTag is the struct tag mapping fields to label and annotation keys, e.g. `backstage:"acme.com/tier,required"`.
+const bindingTag = "backstage"
+
+// BindingError describes a label or annotation that could not be decoded or encoded.
+type BindingError struct {
+	// Source is either "label" or "annotation".
+	Source string
+
+	// Key of the label or annotation.
+	Key string
+
+	// Field is the name of the struct field the label or annotation is bound to.
+	Field string
+
+	// Err is the cause of the error.
+	Err error
+}
+
+// Error returns the error message.
+func (e *BindingError) Error() string {
+	return fmt.Sprintf("%s %s (field %s): %v", e.Source, e.Key, e.Field, e.Err)
+}
+
+// Unwrap returns the cause of the error.
+func (e *BindingError) Unwrap() error {
+	return e.Err
+}
+
+// ErrMissingValue is returned, wrapped in BindingError, when a required label or annotation is missing or empty.
+var ErrMissingValue = errors.New("missing required value")
+
+// DecodeAnnotations decodes the annotations into the struct pointed to by v. Fields are bound to annotations by struct tags
+// with the annotation key and, optionally, the "required" option:
+//
+//	type ownership struct {
+//	        Tier       int           `backstage:"acme.com/tier,required"`
+//	        CostCenter string        `backstage:"acme.com/cost-center"`
+//	        Rotations  []string      `backstage:"acme.com/on-call-rotations"`
+//	        Timeout    time.Duration `backstage:"acme.com/timeout"`
+//	}
+//
+// Strings, booleans, integers, floats, durations, types implementing encoding.TextUnmarshaler, pointers to these, and slices
+// of these decoded from comma-separated lists are supported. Fields of missing annotations are left unchanged. Errors of all
+// fields are returned together, each as BindingError.
+func (m *EntityMeta) DecodeAnnotations(v interface{}) error {
+	return decodeBinding("annotation", m.Annotations, v)
+}
+
+// EncodeAnnotations encodes the struct pointed to by v into the annotations, as bound by struct tags (see DecodeAnnotations).
+// Fields with the "omitempty" option, and nil pointers, are skipped if they have zero value. Required fields formatted as an
+// empty string, e.g. empty strings and nil pointers, are reported as missing, while zero numbers and booleans are encoded.
+func (m *EntityMeta) EncodeAnnotations(v interface{}) error {
+	if m.Annotations == nil {
+		m.Annotations = map[string]string{}
+	}
+
+	return encodeBinding("annotation", m.Annotations, v)
+}
+
+// DecodeLabels decodes the labels into the struct pointed to by v, as bound by struct tags (see DecodeAnnotations).
+func (m *EntityMeta) DecodeLabels(v interface{}) error {
+	return decodeBinding("label", m.Labels, v)
+}
+
+// EncodeLabels encodes the struct pointed to by v into the labels, as bound by struct tags (see EncodeAnnotations).
+func (m *EntityMeta) EncodeLabels(v interface{}) error {
+	if m.Labels == nil {
+		m.Labels = map[string]string{}
+	}
+
+	return encodeBinding("label", m.Labels, v)
+}
+
+// bindingField is a struct field bound to a label or annotation.
+type bindingField struct {
+	name      string
+	key       string
+	required  bool
+	omitempty bool
+	value     reflect.Value
+}
+
+// bindingFields returns the bound fields of the struct pointed to by v.
+func bindingFields(v interface{}) ([]bindingField, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("binding requires a non-nil pointer to a struct, got %T", v)
+	}
+
+	rv = rv.Elem()
+	var fields []bindingField
+	for i := 0; i < rv.NumField(); i++ {
+		f := rv.Type().Field(i)
+		tag, ok := f.Tag.Lookup(bindingTag)
+		if !ok || tag == "-" || !f.IsExported() {
+			continue
+		}
+
+		key, options, _ := strings.Cut(tag, ",")
+		if key == "" {
+			return nil, fmt.Errorf("binding of field %s has no key", f.Name)
+		}
+
+		field := bindingField{name: f.Name, key: key, value: rv.Field(i)}
+		for _, o := range strings.Split(options, ",") {
+			switch o {
+			case "required":
+				field.required = true
+			case "omitempty":
+				field.omitempty = true
+			case "":
+			default:
+				return nil, fmt.Errorf("binding of field %s has unknown option %q", f.Name, o)
+			}
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// decodeBinding decodes the values into the bound fields of the struct pointed to by v.
+func decodeBinding(source string, values map[string]string, v interface{}) error {
+	fields, err := bindingFields(v)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, f := range fields {
+		s, ok := values[f.key]
+		if !ok || s == "" {
+			if f.required {
+				errs = append(errs, &BindingError{Source: source, Key: f.key, Field: f.name, Err: ErrMissingValue})
+			}
+
+			continue
+		}
+
+		if err := parseBindingValue(f.value, s); err != nil {
+			errs = append(errs, &BindingError{Source: source, Key: f.key, Field: f.name, Err: err})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// encodeBinding encodes the bound fields of the struct pointed to by v into the values. Values are only changed if all fields
+// are encoded successfully.
+func encodeBinding(source string, values map[string]string, v interface{}) error {
+	fields, err := bindingFields(v)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	encoded := map[string]string{}
+	for _, f := range fields {
+		if !f.required && f.value.IsZero() && (f.omitempty || f.value.Kind() == reflect.Pointer) {
+			continue
+		}
+
+		s, err := formatBindingValue(f.value)
+		if err != nil {
+			errs = append(errs, &BindingError{Source: source, Key: f.key, Field: f.name, Err: err})
+			continue
+		}
+
+		// Zero numbers and booleans are legitimate values, so only a value formatted as an empty string is missing.
+		if s == "" && f.required {
+			errs = append(errs, &BindingError{Source: source, Key: f.key, Field: f.name, Err: ErrMissingValue})
+			continue
+		}
+
+		encoded[f.key] = s
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	for k, s := range encoded {
+		values[k] = s
+	}
+
+	return nil
+}
+
+// durationType is the type of time.Duration, which is decoded from its string form rather than as an integer.
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// parseBindingValue parses the string into the field.
+func parseBindingValue(v reflect.Value, s string) error {
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(int64(d))
+
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Pointer:
+		p := reflect.New(v.Type().Elem())
+		if err := parseBindingValue(p.Elem(), s); err != nil {
+			return err
+		}
+		v.Set(p)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := parseBindingValue(slice.Index(i), item); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+		v.Set(slice)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
+
+// formatBindingValue formats the field as a string.
+func formatBindingValue(v reflect.Value) (string, error) {
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			return "", nil
+		}
+
+		b, err := m.MarshalText()
+		return string(b), err
+	}
+
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String(), nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	case reflect.Pointer:
+		if v.IsNil() {
+			return "", nil
+		}
+
+		return formatBindingValue(v.Elem())
+	case reflect.Slice:
+		items := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			item, err := formatBindingValue(v.Index(i))
+			if err != nil {
+				return "", fmt.Errorf("item %d: %w", i, err)
+			}
+
+			if strings.Contains(item, ",") {
+				return "", fmt.Errorf("item %d cannot contain a comma: %q", i, item)
+			}
+
+			items = append(items, item)
+		}
+
+		return strings.Join(items, ","), nil
+	default:
+		return "", fmt.Errorf("unsupported type %s", v.Type())
+	}
+}
@@ -0,0 +1,163 @@
+package backstage
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// bound is a struct bound to labels and annotations in tests.
+type bound struct {
+	Tier       int           `backstage:"acme.com/tier,required"`
+	CostCenter string        `backstage:"acme.com/cost-center,omitempty"`
+	Critical   bool          `backstage:"acme.com/critical"`
+	Timeout    time.Duration `backstage:"acme.com/timeout"`
+	Rotations  []string      `backstage:"acme.com/rotations,omitempty"`
+	Ports      []uint16      `backstage:"acme.com/ports,omitempty"`
+	Budget     *float64      `backstage:"acme.com/budget"`
+	Gateway    net.IP        `backstage:"acme.com/gateway,omitempty"`
+	Ignored    string        `backstage:"-"`
+	Untagged   string
+}
+
+// TestEntityMetaDecodeAnnotations tests decoding of annotations into a struct.
+func TestEntityMetaDecodeAnnotations(t *testing.T) {
+	m := EntityMeta{
+		Annotations: map[string]string{
+			"acme.com/tier":      "2",
+			"acme.com/critical":  "true",
+			"acme.com/timeout":   "1m30s",
+			"acme.com/rotations": "primary, secondary,",
+			"acme.com/ports":     "80,443",
+			"acme.com/budget":    "1250.5",
+			"acme.com/gateway":   "10.0.0.1",
+		},
+	}
+
+	var actual bound
+	err := m.DecodeAnnotations(&actual)
+
+	budget := 1250.5
+	expected := bound{
+		Tier:      2,
+		Critical:  true,
+		Timeout:   90 * time.Second,
+		Rotations: []string{"primary", "secondary"},
+		Ports:     []uint16{80, 443},
+		Budget:    &budget,
+		Gateway:   net.ParseIP("10.0.0.1"),
+	}
+
+	assert.NoError(t, err, "DecodeAnnotations should not return an error")
+	assert.Equal(t, expected, actual, "DecodeAnnotations should convert the values")
+}
+
+// TestEntityMetaDecodeAnnotations_Errors tests that errors of all fields are returned.
+func TestEntityMetaDecodeAnnotations_Errors(t *testing.T) {
+	m := EntityMeta{
+		Annotations: map[string]string{
+			"acme.com/critical": "maybe",
+			"acme.com/ports":    "80,http",
+		},
+	}
+
+	var actual bound
+	err := m.DecodeAnnotations(&actual)
+
+	var bindingErr *BindingError
+	assert.ErrorAs(t, err, &bindingErr, "Errors should be binding errors")
+	assert.ErrorIs(t, err, ErrMissingValue, "Missing required annotation should be reported")
+	assert.EqualError(t, err, `annotation acme.com/tier (field Tier): missing required value
+annotation acme.com/critical (field Critical): strconv.ParseBool: parsing "maybe": invalid syntax
+annotation acme.com/ports (field Ports): item 1: strconv.ParseUint: parsing "http": invalid syntax`,
+		"Errors of all fields should be returned")
+
+	err = m.DecodeAnnotations(actual)
+	assert.EqualError(t, err, "binding requires a non-nil pointer to a struct, got backstage.bound", "Non-pointer should be rejected")
+}
+
+// TestEntityMetaEncodeAnnotations tests encoding of a struct into annotations.
+func TestEntityMetaEncodeAnnotations(t *testing.T) {
+	m := EntityMeta{Annotations: map[string]string{"acme.com/other": "kept"}}
+
+	err := m.EncodeAnnotations(&bound{
+		Tier:      1,
+		Timeout:   time.Minute,
+		Rotations: []string{"primary", "secondary"},
+		Gateway:   net.ParseIP("10.0.0.1"),
+	})
+
+	assert.NoError(t, err, "EncodeAnnotations should not return an error")
+	assert.Equal(t, map[string]string{
+		"acme.com/other":     "kept",
+		"acme.com/tier":      "1",
+		"acme.com/critical":  "false",
+		"acme.com/timeout":   "1m0s",
+		"acme.com/rotations": "primary,secondary",
+		"acme.com/gateway":   "10.0.0.1",
+	}, m.Annotations, "EncodeAnnotations should format the values")
+
+	var decoded bound
+	assert.NoError(t, m.DecodeAnnotations(&decoded), "Encoded annotations should be decoded")
+	assert.Equal(t, 1, decoded.Tier, "Encoded annotations should round-trip")
+}
+
+// TestEntityMetaEncodeAnnotations_Required tests that required fields are missing only if they are formatted as empty strings.
+func TestEntityMetaEncodeAnnotations_Required(t *testing.T) {
+	type required struct {
+		Tier     int      `backstage:"acme.com/tier,required"`
+		Critical bool     `backstage:"acme.com/critical,required"`
+		Team     string   `backstage:"acme.com/team,required"`
+		Budget   *float64 `backstage:"acme.com/budget,required"`
+	}
+
+	var m EntityMeta
+	err := m.EncodeAnnotations(&required{})
+
+	var bindingErr *BindingError
+	assert.True(t, errors.Is(err, ErrMissingValue), "Empty required values should be reported")
+	assert.True(t, errors.As(err, &bindingErr), "Error should be a BindingError")
+	assert.ErrorContains(t, err, "acme.com/team", "Empty string should be reported")
+	assert.ErrorContains(t, err, "acme.com/budget", "Nil pointer should be reported")
+	assert.NotContains(t, err.Error(), "acme.com/tier", "Zero integer should not be reported")
+	assert.NotContains(t, err.Error(), "acme.com/critical", "False boolean should not be reported")
+
+	budget := 0.0
+	err = m.EncodeAnnotations(&required{Team: "payments", Budget: &budget})
+	assert.NoError(t, err, "Zero required values should be encoded")
+	assert.Equal(t, map[string]string{
+		"acme.com/tier":     "0",
+		"acme.com/critical": "false",
+		"acme.com/team":     "payments",
+		"acme.com/budget":   "0",
+	}, m.Annotations, "Zero required values should be formatted")
+}
+
+// TestEntityMetaEncodeLabels_Errors tests that labels are not changed if any field cannot be encoded.
+func TestEntityMetaEncodeLabels_Errors(t *testing.T) {
+	var m EntityMeta
+
+	err := m.EncodeLabels(&struct {
+		Team      string   `backstage:"acme.com/team,required"`
+		Rotations []string `backstage:"acme.com/rotations"`
+	}{Rotations: []string{"a,b"}})
+
+	assert.True(t, errors.Is(err, ErrMissingValue), "Missing required label should be reported")
+	assert.ErrorContains(t, err, `label acme.com/rotations (field Rotations): item 0 cannot contain a comma: "a,b"`,
+		"Invalid list item should be reported")
+	assert.Empty(t, m.Labels, "Labels should not be changed")
+}
+
+// TestEntityMetaDecodeLabels tests decoding of labels into a struct.
+func TestEntityMetaDecodeLabels(t *testing.T) {
+	m := EntityMeta{Labels: map[string]string{"acme.com/tier": "3"}}
+
+	var actual bound
+	err := m.DecodeLabels(&actual)
+
+	assert.NoError(t, err, "DecodeLabels should not return an error")
+	assert.Equal(t, 3, actual.Tier, "DecodeLabels should convert the values")
+}
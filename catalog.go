@@ -28,6 +28,9 @@ type catalogService struct {
 	// Systems handles communication with the System related methods of the Backstage Catalog API.
 	Systems *systemService
 
+	// Templates handles communication with the Template related methods of the Backstage Catalog API.
+	Templates *templateService
+
 	// Users handles communication with the User related methods of the Backstage Catalog API.
 	Users *userService
 }
@@ -50,6 +53,7 @@ func newCatalogService(c *Client) *catalogService {
 	s.Locations = newLocationService(s.Entities)
 	s.Resources = newResourceService(s.Entities)
 	s.Systems = newSystemService(s.Entities)
+	s.Templates = newTemplateService(s.Entities)
 	s.Users = newUserService(s.Entities)
 
 	return s
@@ -0,0 +1,363 @@
+package backstage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// VolatileFields are the fields ignored by DiffEntities by default: they change on every update of an entity, or are
+// computed by the catalog, rather than reflecting a change of the entity itself.
+var VolatileFields = []string{"metadata.uid", "metadata.etag", "status"}
+
+// DiffOptions specifies the optional parameters of DiffEntities.
+type DiffOptions struct {
+	// IncludeVolatile compares also the VolatileFields, which are ignored by default.
+	IncludeVolatile bool
+
+	// IgnoreFields are dot-separated paths of additional fields to ignore, e.g. "metadata.annotations" or, for keys containing
+	// dots, "metadata.annotations.backstage.io/techdocs-ref".
+	IgnoreFields []string
+}
+
+// EntityDiff contains differences between two sets of entities, keyed by entity references.
+type EntityDiff struct {
+	// Added contains entities present only in the second set, ordered by their references.
+	Added []Entity `json:"added"`
+
+	// Removed contains entities present only in the first set, ordered by their references.
+	Removed []Entity `json:"removed"`
+
+	// Modified contains entities present in both sets, but with different fields, ordered by their references.
+	Modified []EntityChange `json:"modified"`
+}
+
+// EntityChange describes changes of a single entity.
+type EntityChange struct {
+	// Ref is the reference to the changed entity.
+	Ref string `json:"ref"`
+
+	// Changes contains the changed fields, ordered by their paths.
+	Changes []FieldChange `json:"changes"`
+}
+
+// FieldChange describes a change of a single field.
+type FieldChange struct {
+	// Path is the JSON path of the field, e.g. "spec.owner", "spec.dependsOn[0]" or
+	// "metadata.annotations[\"backstage.io/techdocs-ref\"]".
+	Path string `json:"path"`
+
+	// Before is the value of the field in the first set, or nil if the field was added.
+	Before interface{} `json:"before,omitempty"`
+
+	// After is the value of the field in the second set, or nil if the field was removed.
+	After interface{} `json:"after,omitempty"`
+}
+
+// jsonPath is the path of a field, as keys of objects and indices of arrays.
+type jsonPath []interface{}
+
+// DiffEntities compares two sets of entities, e.g. from two snapshots or from a snapshot and a live listing. Entities are
+// matched by their references. Relations are compared regardless of their order.
+func DiffEntities(before []Entity, after []Entity, options *DiffOptions) (*EntityDiff, error) {
+	opts := DiffOptions{}
+	if options != nil {
+		opts = *options
+	}
+
+	ignored := slices.Clone(opts.IgnoreFields)
+	if !opts.IncludeVolatile {
+		ignored = append(ignored, VolatileFields...)
+	}
+
+	b, err := diffIndex(before)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := diffIndex(after)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &EntityDiff{Added: []Entity{}, Removed: []Entity{}, Modified: []EntityChange{}}
+	for _, ref := range sortedKeys(b) {
+		if _, ok := a[ref]; !ok {
+			diff.Removed = append(diff.Removed, b[ref].entity)
+		}
+	}
+
+	for _, ref := range sortedKeys(a) {
+		old, ok := b[ref]
+		if !ok {
+			diff.Added = append(diff.Added, a[ref].entity)
+			continue
+		}
+
+		var changes []FieldChange
+		diffValues(&changes, nil, map[string]interface{}(old.data), map[string]interface{}(a[ref].data), ignored)
+		if len(changes) > 0 {
+			r := a[ref].entity.Ref()
+			diff.Modified = append(diff.Modified, EntityChange{Ref: r.String(), Changes: changes})
+		}
+	}
+
+	return diff, nil
+}
+
+// DiffSnapshots compares entities of two snapshots (see DiffEntities).
+func DiffSnapshots(before *Snapshot, after *Snapshot, options *DiffOptions) (*EntityDiff, error) {
+	return DiffEntities(before.Entities(), after.Entities(), options)
+}
+
+// Empty returns true if there are no differences.
+func (d *EntityDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// WriteJSON writes the differences as indented JSON.
+func (d *EntityDiff) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(d)
+}
+
+// WriteText writes the differences as a human-readable unified diff, ordered by entity references: added and removed entities
+// in full, and changed fields of modified entities in hunks headed by their paths, e.g.:
+//
+//	--- a/component:default/web
+//	+++ b/component:default/web
+//	@@ spec.owner @@
+//	-"group:default/team-a"
+//	+"group:default/team-b"
+func (d *EntityDiff) WriteText(w io.Writer) error {
+	type textEntry struct {
+		ref      string
+		from, to string
+		changes  []FieldChange
+	}
+
+	var entries []textEntry
+	for _, e := range d.Removed {
+		ref := e.Ref()
+		entries = append(entries, textEntry{ref.String(), "a/" + ref.String(), "/dev/null", []FieldChange{{Before: e}}})
+	}
+
+	for _, e := range d.Added {
+		ref := e.Ref()
+		entries = append(entries, textEntry{ref.String(), "/dev/null", "b/" + ref.String(), []FieldChange{{After: e}}})
+	}
+
+	for _, c := range d.Modified {
+		entries = append(entries, textEntry{c.Ref, "a/" + c.Ref, "b/" + c.Ref, c.Changes})
+	}
+
+	slices.SortStableFunc(entries, func(a, b textEntry) int {
+		return strings.Compare(a.ref, b.ref)
+	})
+
+	bw := bufio.NewWriter(w)
+	for _, e := range entries {
+		fmt.Fprintf(bw, "--- %s\n+++ %s\n", e.from, e.to)
+
+		for _, c := range e.changes {
+			if c.Path != "" {
+				fmt.Fprintf(bw, "@@ %s @@\n", c.Path)
+			}
+
+			if err := writeTextValue(bw, "-", c.Before); err != nil {
+				return err
+			}
+
+			if err := writeTextValue(bw, "+", c.After); err != nil {
+				return err
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+// String returns the path in JSON path form.
+func (p jsonPath) String() string {
+	var b strings.Builder
+	for _, s := range p {
+		switch s := s.(type) {
+		case int:
+			fmt.Fprintf(&b, "[%d]", s)
+		case string:
+			if isPlainKey(s) {
+				if b.Len() > 0 {
+					b.WriteByte('.')
+				}
+				b.WriteString(s)
+			} else {
+				fmt.Fprintf(&b, "[%s]", strconv.Quote(s))
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// ignored returns true if the path is one of the ignored fields, or is nested in one.
+func (p jsonPath) ignored(fields []string) bool {
+	for _, f := range fields {
+		if p.nestedIn(strings.Split(f, ".")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nestedIn returns true if the path starts with the dot-separated path segments. Since map keys (e.g. annotation keys) can
+// contain dots themselves, a key can match several leading segments, as in lookupPath.
+func (p jsonPath) nestedIn(segments []string) bool {
+	if len(segments) == 0 {
+		return true
+	}
+
+	if len(p) == 0 {
+		return false
+	}
+
+	key, ok := p[0].(string)
+	if !ok {
+		return false
+	}
+
+	for i := len(segments); i > 0; i-- {
+		if key == strings.Join(segments[:i], ".") && p[1:].nestedIn(segments[i:]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// diffIndex returns the entities prepared for comparison, keyed by their references, with relations sorted.
+func diffIndex(entities []Entity) (map[string]*indexedEntity, error) {
+	index := map[string]*indexedEntity{}
+	for _, e := range entities {
+		indexed, err := newIndexedEntity(e)
+		if err != nil {
+			return nil, err
+		}
+
+		if relations, ok := indexed.data["relations"].([]interface{}); ok {
+			slices.SortFunc(relations, func(a, b interface{}) int {
+				return strings.Compare(relationKey(a), relationKey(b))
+			})
+		}
+
+		index[refKey(e.Ref())] = indexed
+	}
+
+	return index, nil
+}
+
+// diffValues appends the changes between the values at the path to the changes.
+func diffValues(changes *[]FieldChange, path jsonPath, before interface{}, after interface{}, ignored []string) {
+	if path.ignored(ignored) || reflect.DeepEqual(before, after) {
+		return
+	}
+
+	switch b := before.(type) {
+	case map[string]interface{}:
+		if a, ok := after.(map[string]interface{}); ok {
+			keys := map[string]bool{}
+			for k := range b {
+				keys[k] = true
+			}
+			for k := range a {
+				keys[k] = true
+			}
+
+			for _, k := range sortedKeys(keys) {
+				diffValues(changes, append(slices.Clone(path), k), b[k], a[k], ignored)
+			}
+
+			return
+		}
+	case []interface{}:
+		if a, ok := after.([]interface{}); ok {
+			for i := 0; i < max(len(a), len(b)); i++ {
+				var bi, ai interface{}
+				if i < len(b) {
+					bi = b[i]
+				}
+				if i < len(a) {
+					ai = a[i]
+				}
+
+				diffValues(changes, append(slices.Clone(path), i), bi, ai, ignored)
+			}
+
+			return
+		}
+	}
+
+	*changes = append(*changes, FieldChange{Path: path.String(), Before: before, After: after})
+}
+
+// writeTextValue writes the value as indented JSON, each line prefixed. Nil values are not written.
+func writeTextValue(w io.Writer, prefix string, v interface{}) error {
+	if v == nil {
+		return nil
+	}
+
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	for _, line := range strings.Split(string(b), "\n") {
+		if _, err := fmt.Fprintf(w, "%s%s\n", prefix, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// relationKey returns the key relations are sorted by.
+func relationKey(r interface{}) string {
+	m, _ := r.(map[string]interface{})
+	t, _ := m["type"].(string)
+	target, _ := m["targetRef"].(string)
+
+	return t + " " + target
+}
+
+// isPlainKey returns true if the key can be written in a dot-separated path.
+func isPlainKey(k string) bool {
+	if k == "" {
+		return false
+	}
+
+	for _, c := range k {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sortedKeys returns the keys of the map in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
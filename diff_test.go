@@ -0,0 +1,140 @@
+package backstage
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// diffEntities returns two sets of entities with an added, a removed, a modified and an unchanged entity.
+func diffEntities() ([]Entity, []Entity) {
+	component := func(name string, owner string, uid string) Entity {
+		return Entity{
+			Kind: KindComponent,
+			Metadata: EntityMeta{
+				Name:        name,
+				Namespace:   DefaultNamespaceName,
+				UID:         uid,
+				Etag:        uid,
+				Annotations: map[string]string{AnnotationTechDocsRef: "dir:."},
+			},
+			Spec: map[string]interface{}{"owner": owner, "dependsOn": []interface{}{"resource:db"}},
+			Relations: []EntityRelation{
+				{Type: RelationOwnedBy, TargetRef: "group:default/" + owner},
+				{Type: RelationDependsOn, TargetRef: "resource:default/db"},
+			},
+			Status: &EntityStatus{Items: []EntityStatusItem{{Level: StatusLevelError, Message: uid}}},
+		}
+	}
+
+	before := []Entity{component("web", "team-a", "1"), component("api", "team-a", "2"), component("old", "team-a", "3")}
+
+	web := component("web", "team-b", "4")
+	web.Metadata.Annotations[AnnotationTechDocsRef] = "url:https://example.com/docs"
+	web.Spec["dependsOn"] = []interface{}{"resource:db", "resource:cache"}
+
+	api := component("api", "team-a", "5")
+	api.Relations[0], api.Relations[1] = api.Relations[1], api.Relations[0]
+
+	after := []Entity{web, api, component("new", "team-c", "6")}
+
+	return before, after
+}
+
+// TestDiffEntities tests added, removed and modified entities, with volatile fields and relation order ignored.
+func TestDiffEntities(t *testing.T) {
+	before, after := diffEntities()
+
+	diff, err := DiffEntities(before, after, nil)
+
+	assert.NoError(t, err, "Diff should not return an error")
+	assert.False(t, diff.Empty(), "Diff should not be empty")
+	assert.Len(t, diff.Added, 1, "Diff should contain the added entity")
+	assert.Equal(t, "new", diff.Added[0].Metadata.Name, "Diff should contain the added entity")
+	assert.Len(t, diff.Removed, 1, "Diff should contain the removed entity")
+	assert.Equal(t, "old", diff.Removed[0].Metadata.Name, "Diff should contain the removed entity")
+	assert.Len(t, diff.Modified, 1, "Entities with changed volatile fields or reordered relations should not be modified")
+	assert.Equal(t, "component:default/web", diff.Modified[0].Ref, "Diff should contain the modified entity")
+	assert.Equal(t, []FieldChange{
+		{Path: `metadata.annotations["backstage.io/techdocs-ref"]`, Before: "dir:.", After: "url:https://example.com/docs"},
+		{Path: "relations[1].targetRef", Before: "group:default/team-a", After: "group:default/team-b"},
+		{Path: "spec.dependsOn[1]", After: "resource:cache"},
+		{Path: "spec.owner", Before: "team-a", After: "team-b"},
+	}, diff.Modified[0].Changes, "Diff should contain the changed fields")
+}
+
+// TestDiffEntities_Options tests comparison of volatile fields and ignored fields.
+func TestDiffEntities_Options(t *testing.T) {
+	before, after := diffEntities()
+
+	diff, err := DiffEntities(before, after, &DiffOptions{IncludeVolatile: true, IgnoreFields: []string{"metadata", "relations", "spec"}})
+
+	assert.NoError(t, err, "Diff should not return an error")
+	assert.Len(t, diff.Modified, 2, "Entities with changed volatile fields should be modified")
+	assert.Equal(t, "component:default/api", diff.Modified[0].Ref, "Modified entities should be ordered")
+	assert.Equal(t, "status.items[0].message", diff.Modified[0].Changes[0].Path, "Status should be compared")
+
+	same, err := DiffEntities(before, before, nil)
+	assert.NoError(t, err, "Diff should not return an error")
+	assert.True(t, same.Empty(), "Diff of the same entities should be empty")
+}
+
+// TestDiffEntities_IgnoreDottedKey tests ignoring of a field whose key contains dots, e.g. an annotation.
+func TestDiffEntities_IgnoreDottedKey(t *testing.T) {
+	before, after := diffEntities()
+
+	diff, err := DiffEntities(before, after, &DiffOptions{
+		IgnoreFields: []string{"metadata.annotations." + AnnotationTechDocsRef, "relations", "spec.owner"},
+	})
+
+	assert.NoError(t, err, "Diff should not return an error")
+	assert.Len(t, diff.Modified, 1, "Entity with changes in fields not ignored should be modified")
+	assert.Equal(t, []FieldChange{{Path: "spec.dependsOn[1]", After: "resource:cache"}}, diff.Modified[0].Changes,
+		"Annotation with dots in its key should be ignored")
+
+	diff, err = DiffEntities(before, after, &DiffOptions{IgnoreFields: []string{"metadata.annotations.backstage", "relations", "spec"}})
+	assert.NoError(t, err, "Diff should not return an error")
+	assert.Len(t, diff.Modified, 1, "Partial key should not be ignored")
+}
+
+// TestEntityDiffWriteJSON tests encoding of the diff as JSON.
+func TestEntityDiffWriteJSON(t *testing.T) {
+	before, after := diffEntities()
+	diff, _ := DiffEntities(before, after, nil)
+
+	var buf bytes.Buffer
+	err := diff.WriteJSON(&buf)
+
+	assert.NoError(t, err, "Writing JSON should not return an error")
+
+	var decoded EntityDiff
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded), "Written JSON should be valid")
+	assert.Equal(t, diff.Modified, decoded.Modified, "Written JSON should contain the changes")
+	assert.Len(t, decoded.Added, 1, "Written JSON should contain the added entities")
+}
+
+// TestEntityDiffWriteText tests writing of the diff as unified diff text.
+func TestEntityDiffWriteText(t *testing.T) {
+	before, after := diffEntities()
+	diff, _ := DiffEntities(before[:2], after[:2], &DiffOptions{IgnoreFields: []string{"metadata.annotations", "relations", "spec.dependsOn"}})
+
+	var buf bytes.Buffer
+	err := diff.WriteText(&buf)
+
+	assert.NoError(t, err, "Writing text should not return an error")
+	assert.Equal(t, `--- a/component:default/web
++++ b/component:default/web
+@@ spec.owner @@
+-"team-a"
++"team-b"
+`, buf.String(), "Text should contain hunks of the changed fields")
+
+	diff, _ = DiffEntities(nil, []Entity{{Kind: KindGroup, Metadata: EntityMeta{Name: "team-a"}}}, nil)
+	buf.Reset()
+	err = diff.WriteText(&buf)
+
+	assert.NoError(t, err, "Writing text should not return an error")
+	assert.Contains(t, buf.String(), "--- /dev/null\n+++ b/group:default/team-a\n+{\n", "Added entities should be written in full")
+}
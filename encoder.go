@@ -0,0 +1,188 @@
+package backstage
+
+import (
+	"io"
+	"reflect"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EntityEncoder writes entities as YAML documents, in the conventional form of catalog-info.yaml files:
+//
+//   - Keys are ordered as the fields of the structs, i.e. "apiVersion", "kind", "metadata" and "spec" first, and named by
+//     their YAML tags. Spec keys not modelled by a typed kind follow in alphabetical order.
+//   - Fields populated by the catalog ("metadata.uid", "metadata.etag", "relations" and "status") are removed.
+//   - Empty values, i.e. nulls, empty strings, mappings and sequences, are removed, except for spec fields required by the
+//     schema of the kind and present in the spec, e.g. empty children of groups. Spec fields of typed kinds are required
+//     unless tagged omitempty.
+//
+// Every entity is written as a separate document, so files written by the encoder can be read by LoadEntities.
+type EntityEncoder struct {
+	enc *yaml.Encoder
+}
+
+// NewEntityEncoder returns an encoder writing to w. The encoder must be closed after the last entity is written.
+func NewEntityEncoder(w io.Writer) *EntityEncoder {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+
+	return &EntityEncoder{enc: enc}
+}
+
+// Encode writes the entity as a YAML document. Both *Entity and typed kinds, e.g. *ComponentEntityV1alpha1, are supported.
+func (e *EntityEncoder) Encode(t TypedEntity) error {
+	node, err := entityNode(t)
+	if err != nil {
+		return err
+	}
+
+	return e.enc.Encode(node)
+}
+
+// Close flushes any buffered data to the writer.
+func (e *EntityEncoder) Close() error {
+	return e.enc.Close()
+}
+
+// WriteEntities writes the entities as YAML documents (see EntityEncoder).
+func WriteEntities(w io.Writer, entities ...TypedEntity) error {
+	enc := NewEntityEncoder(w)
+	for _, t := range entities {
+		if err := enc.Encode(t); err != nil {
+			return err
+		}
+	}
+
+	return enc.Close()
+}
+
+// entityNode returns the entity as a YAML node, cleaned up for writing.
+func entityNode(t TypedEntity) (*yaml.Node, error) {
+	e, err := FromTyped(t)
+	if err != nil {
+		return nil, err
+	}
+
+	node := &yaml.Node{}
+	if err := node.Encode(e); err != nil {
+		return nil, err
+	}
+
+	if spec := typedSpec(t); spec != nil {
+		typed := &yaml.Node{}
+		if err := typed.Encode(spec); err != nil {
+			return nil, err
+		}
+
+		// Spec keys not modelled by the typed kind are kept, after the typed ones.
+		if existing := mappingValue(node, "spec"); existing != nil && typed.Kind == yaml.MappingNode {
+			known := specKeys(t)
+			for i := 0; i+1 < len(existing.Content); i += 2 {
+				if !known[existing.Content[i].Value] {
+					typed.Content = append(typed.Content, existing.Content[i], existing.Content[i+1])
+				}
+			}
+
+			*existing = *typed
+		}
+	}
+
+	removeKeys(node, "relations", "status")
+	if metadata := mappingValue(node, "metadata"); metadata != nil {
+		removeKeys(metadata, "uid", "etag")
+	}
+
+	keep := map[string]bool{}
+	for k := range requiredSpecKeys(t) {
+		if v, ok := e.Spec[k]; ok && v != nil {
+			keep["spec."+k] = true
+		}
+	}
+	pruneEmpty(node, "", keep)
+
+	return node, nil
+}
+
+// typedSpec returns the spec of the typed kind, or nil if the entity is not typed or has no spec.
+func typedSpec(t TypedEntity) interface{} {
+	v := reflect.ValueOf(t)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	spec := v.FieldByName("Spec")
+	if spec.Kind() == reflect.Pointer && !spec.IsNil() && spec.Elem().Kind() == reflect.Struct ||
+		spec.Kind() == reflect.Struct {
+		return spec.Interface()
+	}
+
+	return nil
+}
+
+// mappingValue returns the value of the key in the mapping node, or nil if there is none.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// removeKeys removes the keys from the mapping node.
+func removeKeys(node *yaml.Node, keys ...string) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+
+	content := node.Content[:0]
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if !slices.Contains(keys, node.Content[i].Value) {
+			content = append(content, node.Content[i], node.Content[i+1])
+		}
+	}
+	node.Content = content
+}
+
+// pruneEmpty removes empty values from mappings in the node at the dotted path and its children, and returns true if the node
+// itself is empty. Values at the paths in keep are not removed, and items of sequences are kept, even if empty, as removing
+// them would change the meaning of the sequence.
+func pruneEmpty(node *yaml.Node, path string, keep map[string]bool) bool {
+	switch node.Kind {
+	case yaml.MappingNode:
+		content := node.Content[:0]
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			p := node.Content[i].Value
+			if path != "" {
+				p = path + "." + p
+			}
+
+			if !pruneEmpty(node.Content[i+1], p, keep) || keep[p] {
+				content = append(content, node.Content[i], node.Content[i+1])
+			}
+		}
+		node.Content = content
+
+		return len(content) == 0
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			pruneEmpty(item, "", nil)
+		}
+
+		return len(node.Content) == 0
+	case yaml.ScalarNode:
+		return node.Tag == "!!null" || node.Tag == "!!str" && node.Value == ""
+	}
+
+	return false
+}
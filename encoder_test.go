@@ -0,0 +1,125 @@
+package backstage
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWriteEntities tests writing of generic and typed entities as YAML documents.
+func TestWriteEntities(t *testing.T) {
+	group := &Entity{
+		ApiVersion: "backstage.io/v1alpha1",
+		Kind:       KindGroup,
+		Metadata: EntityMeta{
+			UID:         "1",
+			Etag:        "e1",
+			Name:        "team-a",
+			Namespace:   DefaultNamespaceName,
+			Description: "Team A\nbuilds the petstore.",
+			Labels:      map[string]string{},
+			Tags:        []string{"java", "go"},
+		},
+		Spec: map[string]interface{}{
+			"type":     "team",
+			"children": []interface{}{},
+			"profile":  map[string]interface{}{"displayName": "Team A", "email": ""},
+			"parent":   nil,
+		},
+		Relations: []EntityRelation{{Type: RelationHasMember, TargetRef: "user:default/guest"}},
+		Status:    &EntityStatus{Items: []EntityStatusItem{{Level: StatusLevelInfo}}},
+	}
+
+	component := &ComponentEntityV1alpha1{
+		Entity: Entity{
+			Metadata: EntityMeta{Name: "petstore", Etag: "e2"},
+			Spec:     map[string]interface{}{"owner": "team-b", "tier": float64(1)},
+		},
+		ApiVersion: "backstage.io/v1alpha1",
+		Kind:       KindComponent,
+		Spec: &ComponentEntityV1alpha1Spec{
+			Type:         "service",
+			Lifecycle:    "production",
+			Owner:        "team-a",
+			ProvidesApis: []string{"petstore"},
+		},
+	}
+
+	typedGroup := &GroupEntityV1alpha1{
+		Entity:     Entity{Metadata: EntityMeta{Name: "team-b"}},
+		ApiVersion: "backstage.io/v1alpha1",
+		Kind:       KindGroup,
+		Spec:       &GroupEntityV1alpha1Spec{Type: "team", Children: []string{}},
+	}
+
+	var buf bytes.Buffer
+	err := WriteEntities(&buf, group, component, typedGroup)
+
+	assert.NoError(t, err, "Writing should not return an error")
+	assert.Equal(t, `apiVersion: backstage.io/v1alpha1
+kind: Group
+metadata:
+  name: team-a
+  namespace: default
+  description: |-
+    Team A
+    builds the petstore.
+  tags:
+    - java
+    - go
+spec:
+  children: []
+  profile:
+    displayName: Team A
+  type: team
+---
+apiVersion: backstage.io/v1alpha1
+kind: Component
+metadata:
+  name: petstore
+spec:
+  type: service
+  lifecycle: production
+  owner: team-a
+  providesApis:
+    - petstore
+  tier: 1
+---
+apiVersion: backstage.io/v1alpha1
+kind: Group
+metadata:
+  name: team-b
+spec:
+  type: team
+  children: []
+`, buf.String(), "Entities should be written in the conventional form")
+
+	loaded, err := LoadEntities(context.Background(), &buf, nil)
+	assert.NoError(t, err, "Loading written entities should not return an error")
+	for i := range loaded {
+		assert.NoError(t, Validate(&loaded[i]), "Written entities should be valid")
+	}
+	if assert.Len(t, loaded, 3, "All written entities should be loaded") {
+		assert.Equal(t, []interface{}{}, loaded[2].Spec["children"], "Empty children should be written")
+	}
+}
+
+// TestEntityEncoder tests that written entities can be loaded back.
+func TestEntityEncoder(t *testing.T) {
+	entities, err := LoadEntitiesFile(context.Background(), "testdata/loader/catalog-info.yaml", nil)
+	assert.NoError(t, err, "Loading should not return an error")
+
+	var buf bytes.Buffer
+	enc := NewEntityEncoder(&buf)
+	for i := range entities {
+		assert.NoError(t, enc.Encode(&entities[i]), "Encoding should not return an error")
+	}
+	assert.NoError(t, enc.Close(), "Closing should not return an error")
+
+	loaded, err := LoadEntities(context.Background(), &buf, nil)
+
+	assert.NoError(t, err, "Loading written entities should not return an error")
+	assert.Equal(t, entities, loaded, "Written entities should be loaded unchanged")
+}
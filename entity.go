@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"iter"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 )
 
@@ -170,19 +172,91 @@ type ListEntityOptions struct {
 
 	// Order is a set of conditions that can be used to order entities.
 	Order []ListEntityOrder
+
+	// Offset is the number of entities to skip.
+	Offset int
+
+	// Limit is the maximum number of entities to return.
+	Limit int
+
+	// After is an opaque cursor, returned by the server in the Link header, pointing to the next page of entities.
+	After string
 }
 
-// entityConstraint defines constrains for entity types.
-type entityConstraint interface {
-	ApiEntityV1alpha1 | ComponentEntityV1alpha1 | DomainEntityV1alpha1 | GroupEntityV1alpha1 | LocationEntityV1alpha1 |
-		ResourceEntityV1alpha1 | SystemEntityV1alpha1 | UserEntityV1alpha1
+// EntityPage is a single page of entities returned by the ListAll methods.
+type EntityPage[T any] struct {
+	// Entities contained in the page.
+	Entities []T
+
+	// Offset of the first entity of the page, relative to the offset the listing started from.
+	Offset int
+
+	// Next is the cursor pointing to the next page, if returned by the server. It can be used as ListEntityOptions.After to
+	// resume the listing.
+	Next string
+
+	// Response is the HTTP response the page was decoded from.
+	Response *http.Response
+}
+
+// DeleteEntityOptions specifies the optional parameters to the entityService.DeleteByRef method.
+type DeleteEntityOptions struct {
+	// Etag is the expected value of the entity's metadata.etag. If set, the entity is deleted only if its current etag matches,
+	// as checked by the client right before the deletion.
+	Etag string
+
+	// RequireOrphan makes the deletion fail unless the entity is marked as orphaned.
+	RequireOrphan bool
 }
 
+// EtagMismatchError is returned when the etag of an entity does not match the expected one. The etag is compared on the
+// client before deleting, so its absence does not guarantee that the entity was unchanged when it was deleted.
+type EtagMismatchError struct {
+	// Expected is the etag that was expected.
+	Expected string
+
+	// Actual is the current etag of the entity.
+	Actual string
+}
+
+// defaultPageSize is the number of entities requested per page, when no page size is provided.
+const defaultPageSize = 100
+
 // entityService handles communication with the Backstage entities endpoints in Backstage Catalog API.
 type entityService service
 
 // typedEntityService handles communication with the Backstage entities endpoints in Backstage Catalog API, for a specific type of entity.
-type typedEntityService[T entityConstraint] service
+type typedEntityService[T any] service
+
+var (
+	// ErrEntityNotFound is returned when the requested entity does not exist.
+	ErrEntityNotFound = errors.New("entity not found")
+
+	// ErrEntityNotOrphan is returned when an entity is required to be orphaned, but it is not.
+	ErrEntityNotOrphan = errors.New("entity is not orphaned")
+
+	// ErrEtagMismatch is returned (wrapped in EtagMismatchError) when the etag of an entity does not match the expected one.
+	ErrEtagMismatch = errors.New("entity etag does not match")
+)
+
+// Relation types of the relations between built-in kinds.
+// https://backstage.io/docs/features/software-catalog/well-known-relations
+const (
+	RelationOwnedBy       = "ownedBy"
+	RelationOwnerOf       = "ownerOf"
+	RelationConsumesApi   = "consumesApi"
+	RelationApiConsumedBy = "apiConsumedBy"
+	RelationProvidesApi   = "providesApi"
+	RelationApiProvidedBy = "apiProvidedBy"
+	RelationDependsOn     = "dependsOn"
+	RelationDependencyOf  = "dependencyOf"
+	RelationParentOf      = "parentOf"
+	RelationChildOf       = "childOf"
+	RelationMemberOf      = "memberOf"
+	RelationHasMember     = "hasMember"
+	RelationPartOf        = "partOf"
+	RelationHasPart       = "hasPart"
+)
 
 const (
 	// OrderAscending is used to order entities in ascending order.
@@ -206,6 +280,11 @@ func newEntityService(s *catalogService) *entityService {
 
 // List returns a list of entities. It can optionally be filtered by a set of conditions and limited to a set of fields.
 func (s *entityService) List(ctx context.Context, options *ListEntityOptions) ([]Entity, *http.Response, error) {
+	return list[Entity](ctx, s, options)
+}
+
+// list returns entities matching the options, decoded into the given type.
+func list[T any](ctx context.Context, s *entityService, options *ListEntityOptions) ([]T, *http.Response, error) {
 	u := url.URL{
 		Path: s.apiPath,
 	}
@@ -229,16 +308,133 @@ func (s *entityService) List(ctx context.Context, options *ListEntityOptions) ([
 				}
 			}
 		}
+
+		if options.Offset > 0 {
+			values.Add("offset", strconv.Itoa(options.Offset))
+		}
+
+		if options.Limit > 0 {
+			values.Add("limit", strconv.Itoa(options.Limit))
+		}
+
+		if options.After != "" {
+			values.Add("after", options.After)
+		}
 	}
 
 	req, _ := s.client.newRequest(http.MethodGet, fmt.Sprintf("%s?%s", u.Path, values.Encode()), nil)
 
-	var entities []Entity
+	var entities []T
 	resp, err := s.client.do(ctx, req, &entities)
 
 	return entities, resp, err
 }
 
+// ListAll pages through all entities matching the options, calling fn for each page of at most pageSize entities ("100", if
+// not specified). Pages are requested using the cursor from the Link header when the server returns one, and using offset
+// otherwise. Listing stops at the first error, either returned from the server or by fn, and when paging by offset yields no
+// entity with a UID that was not listed before, e.g. because the server ignores the offset.
+func (s *entityService) ListAll(ctx context.Context, options *ListEntityOptions, pageSize int, fn func(*EntityPage[Entity]) error) error {
+	return listAll(ctx, s, options, pageSize, fn)
+}
+
+// listAll pages through entities matching the options, decoded into the given type, calling fn for each page.
+func listAll[T any](ctx context.Context, s *entityService, options *ListEntityOptions, pageSize int, fn func(*EntityPage[T]) error) error {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	opts := ListEntityOptions{}
+	if options != nil {
+		opts = *options
+	}
+	opts.Limit = pageSize
+
+	seen := map[string]bool{}
+	for offset := 0; ; {
+		entities, resp, err := list[T](ctx, s, &opts)
+		if err != nil {
+			return err
+		}
+
+		if opts.After == "" && len(entities) > 0 && !markSeen(seen, entities) {
+			return nil
+		}
+
+		page := &EntityPage[T]{
+			Entities: entities,
+			Offset:   offset,
+			Next:     nextCursor(resp),
+			Response: resp,
+		}
+
+		if err := fn(page); err != nil {
+			return err
+		}
+
+		offset += len(entities)
+		switch {
+		case len(entities) == 0:
+			return nil
+		case page.Next != "":
+			opts.After, opts.Offset = page.Next, 0
+		case opts.After != "" || len(entities) < pageSize:
+			return nil
+		default:
+			opts.Offset += len(entities)
+		}
+	}
+}
+
+// Stream returns an iterator over all entities matching the options, fetched in pages of at most pageSize entities ("100", if
+// not specified). Iteration stops after the first error is yielded.
+func (s *entityService) Stream(ctx context.Context, options *ListEntityOptions, pageSize int) iter.Seq2[Entity, error] {
+	return stream[Entity](ctx, s, options, pageSize)
+}
+
+// ListTyped returns a list of entities, each converted to the typed kind registered for it (see Entity.Typed).
+func (s *entityService) ListTyped(ctx context.Context, options *ListEntityOptions) ([]TypedEntity, *http.Response, error) {
+	entities, resp, err := s.List(ctx, options)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	typed := make([]TypedEntity, 0, len(entities))
+	for _, e := range entities {
+		t, err := e.Typed()
+		if err != nil {
+			return nil, resp, err
+		}
+
+		typed = append(typed, t)
+	}
+
+	return typed, resp, nil
+}
+
+// errStopStream is used internally to stop paging when the consumer of a stream stops iterating.
+var errStopStream = errors.New("stream stopped")
+
+// stream returns an iterator over entities matching the options, decoded into the given type.
+func stream[T any](ctx context.Context, s *entityService, options *ListEntityOptions, pageSize int) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		err := listAll(ctx, s, options, pageSize, func(page *EntityPage[T]) error {
+			for _, e := range page.Entities {
+				if !yield(e, nil) {
+					return errStopStream
+				}
+			}
+
+			return nil
+		})
+
+		if err != nil && !errors.Is(err, errStopStream) {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
 // Get returns a single entity by its UID.
 func (s *entityService) Get(ctx context.Context, uid string) (*Entity, *http.Response, error) {
 	path, _ := url.JoinPath(s.apiPath, "/by-uid/", uid)
@@ -263,6 +459,68 @@ func (s *entityService) Delete(ctx context.Context, uid string) (*http.Response,
 	return s.client.do(ctx, req, nil)
 }
 
+// DeleteByRef deletes an entity identified by its reference in "kind:[namespace/]name" form (see ParseEntityRef). If the
+// namespace is omitted, the client's default namespace is used. Options can guard the deletion by the expected etag or by
+// requiring the entity to be orphaned; a deletion refused by either returns EtagMismatchError or ErrEntityNotOrphan respectively.
+// The Catalog API has no conditional delete, so the guards are checked against a fetched copy of the entity before it is
+// deleted by UID in a separate request. An entity modified between the two requests is still deleted.
+func (s *entityService) DeleteByRef(ctx context.Context, ref string, options *DeleteEntityOptions) (*http.Response, error) {
+	entity, resp, err := s.getByRef(ctx, ref)
+	if err != nil {
+		return resp, err
+	}
+
+	if options != nil {
+		if options.Etag != "" && options.Etag != entity.Metadata.Etag {
+			return resp, &EtagMismatchError{Expected: options.Etag, Actual: entity.Metadata.Etag}
+		}
+
+		if options.RequireOrphan && !entity.Metadata.IsOrphan() {
+			return resp, ErrEntityNotOrphan
+		}
+	}
+
+	return s.Delete(ctx, entity.Metadata.UID)
+}
+
+// getByRef returns an entity identified by its reference in "kind:[namespace/]name" form.
+func (s *entityService) getByRef(ctx context.Context, ref string) (*Entity, *http.Response, error) {
+	r, err := s.client.ParseEntityRef(ref, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	path, _ := url.JoinPath(s.apiPath, "/by-name/", strings.ToLower(r.Kind), r.Namespace, r.Name)
+	req, _ := s.client.newRequest(http.MethodGet, path, nil)
+
+	var entity *Entity
+	resp, err := s.client.do(ctx, req, &entity)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound || entity == nil || entity.Metadata.UID == "" {
+		return nil, resp, ErrEntityNotFound
+	}
+
+	return entity, resp, nil
+}
+
+// list returns n specific type entities matching the options.
+func (s *typedEntityService[T]) list(ctx context.Context, t string, options *ListEntityOptions) ([]T, *http.Response, error) {
+	return list[T](ctx, (*entityService)(s), withKindFilter(options, t))
+}
+
+// listAll pages through n specific type entities matching the options.
+func (s *typedEntityService[T]) listAll(ctx context.Context, t string, options *ListEntityOptions, pageSize int, fn func(*EntityPage[T]) error) error {
+	return listAll(ctx, (*entityService)(s), withKindFilter(options, t), pageSize, fn)
+}
+
+// stream returns an iterator over n specific type entities matching the options.
+func (s *typedEntityService[T]) stream(ctx context.Context, t string, options *ListEntityOptions, pageSize int) iter.Seq2[T, error] {
+	return stream[T](ctx, (*entityService)(s), withKindFilter(options, t), pageSize)
+}
+
 // get returns n specific type entity identified by the name and the namespace ("default", if not specified) it belongs to.
 func (s *typedEntityService[T]) get(ctx context.Context, t string, n string, ns string) (*T, *http.Response, error) {
 	if ns == "" {
@@ -278,6 +536,94 @@ func (s *typedEntityService[T]) get(ctx context.Context, t string, n string, ns
 	return entity, resp, err
 }
 
+// withKindFilter returns a copy of the options with every filter restricted to the given kind. Since separate filters are
+// ORed, the kind condition is added to each of them.
+func withKindFilter(options *ListEntityOptions, kind string) *ListEntityOptions {
+	opts := ListEntityOptions{}
+	if options != nil {
+		opts = *options
+	}
+
+	cond := "kind=" + strings.ToLower(kind)
+	if len(opts.Filters) == 0 {
+		opts.Filters = []string{cond}
+		return &opts
+	}
+
+	filters := make([]string, 0, len(opts.Filters))
+	for _, f := range opts.Filters {
+		if f == "" {
+			filters = append(filters, cond)
+		} else {
+			filters = append(filters, cond+","+f)
+		}
+	}
+	opts.Filters = filters
+
+	return &opts
+}
+
+// markSeen records the entities by UID and reports whether any of them was not seen before. Pages of entities without UID,
+// e.g. listed with fields excluding "metadata.uid", and of types not embedding Entity are always reported as new.
+func markSeen[T any](seen map[string]bool, entities []T) bool {
+	fresh := false
+	for i := range entities {
+		t, ok := any(&entities[i]).(TypedEntity)
+		if !ok {
+			return true
+		}
+
+		uid := t.entity().Metadata.UID
+		if uid == "" {
+			return true
+		}
+
+		if !seen[uid] {
+			seen[uid] = true
+			fresh = true
+		}
+	}
+
+	return fresh
+}
+
+// nextCursor returns the cursor of the next page from the Link header of the response, or an empty string if there is none.
+func nextCursor(resp *http.Response) string {
+	if resp == nil {
+		return ""
+	}
+
+	for _, header := range resp.Header.Values("Link") {
+		for _, link := range strings.Split(header, ",") {
+			target, params, ok := strings.Cut(strings.TrimSpace(link), ";")
+			if !ok || !strings.Contains(strings.ReplaceAll(params, " ", ""), `rel="next"`) {
+				continue
+			}
+
+			u, err := url.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+			if err != nil {
+				continue
+			}
+
+			if after := u.Query().Get("after"); after != "" {
+				return after
+			}
+		}
+	}
+
+	return ""
+}
+
+// Error returns a description of the etag mismatch.
+func (e *EtagMismatchError) Error() string {
+	return fmt.Sprintf("%s: expected %q, got %q", ErrEtagMismatch, e.Expected, e.Actual)
+}
+
+// Unwrap returns ErrEtagMismatch, so that the error can be checked with errors.Is.
+func (e *EtagMismatchError) Unwrap() error {
+	return ErrEtagMismatch
+}
+
 // string returns a string representation of the ListEntityOrder.
 func (o *ListEntityOrder) string() (string, error) {
 	if o.Direction != OrderAscending && o.Direction != OrderDescending {
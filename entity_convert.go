@@ -0,0 +1,271 @@
+package backstage
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// TypedEntity is implemented by Entity and by every typed entity kind embedding it, e.g. *ComponentEntityV1alpha1.
+type TypedEntity interface {
+	entity() *Entity
+}
+
+// entity returns the generic entity itself.
+func (e *Entity) entity() *Entity {
+	return e
+}
+
+// Typed returns the entity converted to the typed kind registered for its API version and kind, e.g. *ComponentEntityV1alpha1
+// for "Component". Entities of unregistered kinds are returned as *Entity.
+func (e Entity) Typed() (TypedEntity, error) {
+	factory := lookupKind(e.ApiVersion, e.Kind)
+	if factory == nil {
+		return &e, nil
+	}
+
+	t := factory()
+	if err := decodeTyped(e, t); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// As converts the entity to the typed kind T, e.g. ComponentEntityV1alpha1. Both the typed fields and the embedded Entity
+// are populated, so that the entity can be converted back with FromTyped without losing any fields. If T is registered with
+// RegisterKind, an error is returned unless the entity has the registered kind and API version.
+func As[T any, PT interface {
+	*T
+	TypedEntity
+}](e Entity) (T, error) {
+	var t T
+	if r, ok := lookupType(reflect.TypeOf(PT(nil))); ok {
+		if !strings.EqualFold(e.Kind, r.kind) || (r.apiVersion != "" && e.ApiVersion != r.apiVersion) {
+			return t, fmt.Errorf("cannot convert %s entity %s to %T: expected kind %s of API version %s", e.Kind, e.Metadata.Name,
+				t, r.kind, r.apiVersion)
+		}
+	}
+
+	if err := decodeTyped(e, PT(&t)); err != nil {
+		return t, err
+	}
+
+	return t, nil
+}
+
+// FromTyped converts a typed entity back to Entity. Changes made to the typed spec take precedence over the embedded Entity
+// spec, while spec fields not modelled by the typed kind are preserved.
+func FromTyped(t TypedEntity) (Entity, error) {
+	embedded := t.entity()
+	if embedded == t {
+		return *embedded, nil
+	}
+
+	data, err := json.Marshal(t)
+	if err != nil {
+		return Entity{}, err
+	}
+
+	var typed struct {
+		ApiVersion string                 `json:"apiVersion"`
+		Kind       string                 `json:"kind"`
+		Spec       map[string]interface{} `json:"spec"`
+	}
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return Entity{}, err
+	}
+
+	e := *embedded
+	if typed.ApiVersion != "" {
+		e.ApiVersion = typed.ApiVersion
+	}
+
+	if typed.Kind != "" {
+		e.Kind = typed.Kind
+	}
+
+	if typed.Spec != nil {
+		e.Spec = mergeSpec(embedded.Spec, typed.Spec, specKeys(t), requiredSpecKeys(t))
+	}
+
+	return e, nil
+}
+
+// decodeTyped populates the typed entity t from the entity e.
+func decodeTyped(e Entity, t TypedEntity) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, t); err != nil {
+		return fmt.Errorf("cannot convert %s entity %s: %w", e.Kind, e.Metadata.Name, err)
+	}
+
+	*t.entity() = e
+	if e.Spec != nil {
+		t.entity().Spec = copyValue(e.Spec).(map[string]interface{})
+	}
+
+	return nil
+}
+
+// copyValue returns a deep copy of a generic JSON value, so that its maps and slices are not shared with the original.
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(v))
+		for k, x := range v {
+			c[k] = copyValue(x)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for i, x := range v {
+			c[i] = copyValue(x)
+		}
+		return c
+	default:
+		return v
+	}
+}
+
+// mergeSpec merges the spec encoded from a typed entity into the original spec. Keys known to the typed spec are taken from it,
+// unless they hold a zero value and were absent in the original spec; other keys are kept from the original spec. Empty lists
+// and objects of required keys are taken as well, as they can only be set explicitly, e.g. empty children of groups.
+func mergeSpec(original map[string]interface{}, typed map[string]interface{}, known map[string]bool, required map[string]bool) map[string]interface{} {
+	spec := make(map[string]interface{}, len(original)+len(typed))
+	for k, v := range original {
+		if !known[k] {
+			spec[k] = v
+		}
+	}
+
+	for k, v := range typed {
+		if _, ok := original[k]; !ok && isZeroJSON(v) && !(required[k] && isCollectionJSON(v)) {
+			continue
+		}
+
+		spec[k] = v
+	}
+
+	return spec
+}
+
+// specKeys returns JSON keys of the Spec field declared directly on the typed entity.
+func specKeys(t TypedEntity) map[string]bool {
+	keys := map[string]bool{}
+	for name := range specFieldTags(t) {
+		keys[name] = true
+	}
+
+	return keys
+}
+
+// requiredSpecKeys returns JSON keys of the Spec field declared directly on the typed entity that are not tagged omitempty,
+// i.e. required by the schema of the kind. For generic entities, the typed kind registered for the entity's kind is used.
+func requiredSpecKeys(t TypedEntity) map[string]bool {
+	if e := t.entity(); e == t {
+		factory := lookupKind(e.ApiVersion, e.Kind)
+		if factory == nil {
+			return map[string]bool{}
+		}
+
+		t = factory()
+	}
+
+	keys := map[string]bool{}
+	for name, options := range specFieldTags(t) {
+		if !slices.Contains(strings.Split(options, ","), "omitempty") {
+			keys[name] = true
+		}
+	}
+
+	return keys
+}
+
+// specFieldTags returns JSON keys of the Spec field declared directly on the typed entity, mapped to their tag options.
+func specFieldTags(t TypedEntity) map[string]string {
+	tags := map[string]string{}
+
+	typ := reflect.TypeOf(t)
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return tags
+	}
+
+	field, ok := typ.FieldByName("Spec")
+	if !ok {
+		return tags
+	}
+
+	spec := field.Type
+	for spec.Kind() == reflect.Pointer {
+		spec = spec.Elem()
+	}
+
+	if spec.Kind() != reflect.Struct {
+		return tags
+	}
+
+	return jsonFieldTags(spec)
+}
+
+// jsonFieldTags returns JSON keys of the fields of the struct type, mapped to their tag options.
+func jsonFieldTags(typ reflect.Type) map[string]string {
+	tags := map[string]string{}
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+
+		tags[name] = options
+	}
+
+	return tags
+}
+
+// isZeroJSON returns true if v is a zero value decoded from JSON.
+func isZeroJSON(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return true
+	case string:
+		return val == ""
+	case bool:
+		return !val
+	case float64:
+		return val == 0
+	case []interface{}:
+		return len(val) == 0
+	case map[string]interface{}:
+		return len(val) == 0
+	default:
+		return false
+	}
+}
+
+// isCollectionJSON returns true if v is a list or an object decoded from JSON.
+func isCollectionJSON(v interface{}) bool {
+	switch v.(type) {
+	case []interface{}, map[string]interface{}:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,182 @@
+package backstage
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAs tests the conversion of an entity to a typed kind.
+func TestAs(t *testing.T) {
+	const dataFile = "testdata/component.json"
+
+	var entity Entity
+	expectedData, _ := os.ReadFile(dataFile)
+	err := json.Unmarshal(expectedData, &entity)
+
+	assert.FileExists(t, dataFile, "Test data file should exist")
+	assert.NoError(t, err, "Unmarshal should not return an error")
+
+	actual, err := As[ComponentEntityV1alpha1](entity)
+	assert.NoError(t, err, "As should not return an error")
+	assert.Equal(t, KindComponent, actual.Kind, "Kind should be set on the typed entity")
+	assert.Equal(t, KindComponent, actual.Entity.Kind, "Kind should be set on the embedded entity")
+	assert.Equal(t, "guests", actual.Spec.Owner, "Typed spec should be decoded")
+	assert.Equal(t, entity.Spec, actual.Entity.Spec, "Embedded spec should match the entity spec")
+	assert.Equal(t, entity.Metadata, actual.Metadata, "Metadata should be preserved")
+}
+
+// TestAs_Mismatch tests that an entity of another kind or API version is not converted.
+func TestAs_Mismatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiVersion string
+		kind       string
+	}{
+		{name: "kind", apiVersion: ApiVersionV1alpha1, kind: KindUser},
+		{name: "api version", apiVersion: "backstage.io/v1beta1", kind: KindComponent},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entity := Entity{ApiVersion: test.apiVersion, Kind: test.kind, Metadata: EntityMeta{Name: "foo"}}
+
+			_, err := As[ComponentEntityV1alpha1](entity)
+			assert.Error(t, err, "As should return an error for a mismatching entity")
+		})
+	}
+}
+
+// TestAs_SpecCopy tests that the embedded spec of the typed entity is not shared with the converted entity.
+func TestAs_SpecCopy(t *testing.T) {
+	entity := Entity{
+		ApiVersion: ApiVersionV1alpha1,
+		Kind:       KindComponent,
+		Metadata:   EntityMeta{Name: "foo"},
+		Spec: map[string]interface{}{
+			"owner": "guests",
+			"links": []interface{}{map[string]interface{}{"url": "https://example.com"}},
+		},
+	}
+
+	typed, err := As[ComponentEntityV1alpha1](entity)
+	assert.NoError(t, err, "As should not return an error")
+
+	typed.Entity.Spec["owner"] = "admins"
+	typed.Entity.Spec["links"].([]interface{})[0].(map[string]interface{})["url"] = "https://example.org"
+
+	assert.Equal(t, "guests", entity.Spec["owner"], "Entity spec should not be modified")
+	assert.Equal(t, "https://example.com", entity.Spec["links"].([]interface{})[0].(map[string]interface{})["url"],
+		"Nested entity spec values should not be modified")
+}
+
+// TestEntityTyped tests the conversion of an entity to the typed kind chosen by its kind.
+func TestEntityTyped(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     string
+		expected TypedEntity
+	}{
+		{name: "api", kind: "API", expected: &ApiEntityV1alpha1{}},
+		{name: "component", kind: "Component", expected: &ComponentEntityV1alpha1{}},
+		{name: "domain", kind: "domain", expected: &DomainEntityV1alpha1{}},
+		{name: "group", kind: "Group", expected: &GroupEntityV1alpha1{}},
+		{name: "location", kind: "Location", expected: &LocationEntityV1alpha1{}},
+		{name: "resource", kind: "Resource", expected: &ResourceEntityV1alpha1{}},
+		{name: "system", kind: "System", expected: &SystemEntityV1alpha1{}},
+		{name: "user", kind: "User", expected: &UserEntityV1alpha1{}},
+		{name: "template", kind: "Template", expected: &TemplateEntityV1beta3{}},
+		{name: "unknown", kind: "Pipeline", expected: &Entity{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := Entity{Kind: test.kind, Metadata: EntityMeta{Name: "foo"}}.Typed()
+			assert.NoError(t, err, "Typed should not return an error")
+			assert.IsType(t, test.expected, actual, "Typed entity should match the kind")
+			assert.Equal(t, "foo", actual.entity().Metadata.Name, "Metadata should be preserved")
+		})
+	}
+}
+
+// TestEntityTyped_InvalidSpec tests if an error is returned when the spec does not match the typed kind.
+func TestEntityTyped_InvalidSpec(t *testing.T) {
+	_, err := Entity{Kind: KindComponent, Spec: map[string]interface{}{"owner": 42}}.Typed()
+	assert.Error(t, err, "Typed should return an error when spec cannot be decoded")
+}
+
+// TestFromTyped_RoundTrip tests that converting an entity to a typed kind and back does not drop any fields.
+func TestFromTyped_RoundTrip(t *testing.T) {
+	const dataFile = "testdata/entities.json"
+
+	var entities []Entity
+	expectedData, _ := os.ReadFile(dataFile)
+	err := json.Unmarshal(expectedData, &entities)
+
+	assert.FileExists(t, dataFile, "Test data file should exist")
+	assert.NoError(t, err, "Unmarshal should not return an error")
+
+	for _, entity := range entities {
+		entity.Spec["x-custom"] = map[string]interface{}{"foo": "bar"}
+
+		typed, err := entity.Typed()
+		assert.NoError(t, err, "Typed should not return an error")
+
+		actual, err := FromTyped(typed)
+		assert.NoError(t, err, "FromTyped should not return an error")
+		assert.Equal(t, entity, actual, "Round-tripped entity should match the original one")
+	}
+}
+
+// TestFromTyped_Modified tests that changes made to the typed spec are applied when converting back.
+func TestFromTyped_Modified(t *testing.T) {
+	entity := Entity{
+		ApiVersion: "backstage.io/v1alpha1",
+		Kind:       KindComponent,
+		Metadata:   EntityMeta{Name: "foo"},
+		Spec: map[string]interface{}{
+			"type":     "service",
+			"owner":    "guests",
+			"system":   "examples",
+			"x-custom": "value",
+		},
+	}
+
+	typed, err := As[ComponentEntityV1alpha1](entity)
+	assert.NoError(t, err, "As should not return an error")
+
+	typed.Spec.Owner = "admins"
+	typed.Spec.System = ""
+	typed.Spec.DependsOn = []string{"resource:db"}
+
+	actual, err := FromTyped(&typed)
+	assert.NoError(t, err, "FromTyped should not return an error")
+	assert.Equal(t, "admins", actual.Spec["owner"], "Modified field should be applied")
+	assert.NotContains(t, actual.Spec, "system", "Cleared field should be removed")
+	assert.Equal(t, []interface{}{"resource:db"}, actual.Spec["dependsOn"], "Added field should be applied")
+	assert.Equal(t, "value", actual.Spec["x-custom"], "Unknown field should be preserved")
+	assert.NotContains(t, actual.Spec, "lifecycle", "Zero field absent in the original spec should not be added")
+	assert.Equal(t, "service", entity.Spec["type"], "Original entity should not be modified")
+}
+
+// TestFromTyped_RequiredEmpty tests that explicitly empty lists of required spec fields are kept when converting back.
+func TestFromTyped_RequiredEmpty(t *testing.T) {
+	group := &GroupEntityV1alpha1{
+		Entity:     Entity{Metadata: EntityMeta{Name: "team-a"}},
+		ApiVersion: "backstage.io/v1alpha1",
+		Kind:       KindGroup,
+		Spec:       &GroupEntityV1alpha1Spec{Type: "team"},
+	}
+
+	actual, err := FromTyped(group)
+	assert.NoError(t, err, "FromTyped should not return an error")
+	assert.NotContains(t, actual.Spec, "children", "Unset children should not be added")
+
+	group.Spec.Children = []string{}
+	actual, err = FromTyped(group)
+	assert.NoError(t, err, "FromTyped should not return an error")
+	assert.Equal(t, []interface{}{}, actual.Spec["children"], "Empty children should be kept")
+	assert.NotContains(t, actual.Spec, "members", "Empty optional members should not be added")
+}
@@ -0,0 +1,150 @@
+package backstage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EntityRef is a reference to an entity, identified by its kind, namespace and name.
+// https://backstage.io/docs/features/software-catalog/references
+type EntityRef struct {
+	// Kind of the referenced entity.
+	Kind string
+
+	// Namespace of the referenced entity.
+	Namespace string
+
+	// Name of the referenced entity.
+	Name string
+}
+
+// EntityRefDefaults specifies values used for the parts missing from a parsed entity reference.
+type EntityRefDefaults struct {
+	// Kind is used if the reference does not contain a kind. If empty, the kind is required in the reference.
+	Kind string
+
+	// Namespace is used if the reference does not contain a namespace. If empty, "default" is used.
+	Namespace string
+}
+
+// ParseEntityRef parses an entity reference in "[<kind>:][<namespace>/]<name>" form. The kind and namespace can be omitted
+// from the reference, in which case the defaults are used; the default kind depends on the context the reference is used in,
+// e.g. an owner reference defaults to the "Group" kind.
+func ParseEntityRef(ref string, defaults EntityRefDefaults) (EntityRef, error) {
+	colon := strings.Index(ref, ":")
+	slash := strings.Index(ref, "/")
+	if slash != -1 && slash < colon {
+		colon = -1
+	}
+
+	r := EntityRef{
+		Kind:      defaults.Kind,
+		Namespace: defaults.Namespace,
+		Name:      ref[max(colon+1, slash+1):],
+	}
+
+	if colon != -1 {
+		r.Kind = ref[:colon]
+	}
+
+	if slash != -1 {
+		r.Namespace = ref[colon+1 : slash]
+	}
+
+	switch {
+	case r.Kind == "":
+		return EntityRef{}, fmt.Errorf("invalid entity ref %q: kind is missing", ref)
+	case r.Namespace == "" && slash != -1:
+		return EntityRef{}, fmt.Errorf("invalid entity ref %q: namespace is empty", ref)
+	case r.Name == "":
+		return EntityRef{}, fmt.Errorf("invalid entity ref %q: name is missing", ref)
+	}
+
+	if r.Namespace == "" {
+		r.Namespace = DefaultNamespaceName
+	}
+
+	return r, nil
+}
+
+// ParseEntityRef parses an entity reference, using the client's default namespace and the given default kind for the parts
+// missing from the reference.
+func (c *Client) ParseEntityRef(ref string, defaultKind string) (EntityRef, error) {
+	return ParseEntityRef(ref, EntityRefDefaults{Kind: defaultKind, Namespace: c.DefaultNamespace})
+}
+
+// String returns the canonical string representation of the reference, "<kind>:<namespace>/<name>", with kind and namespace
+// in lowercase.
+func (r EntityRef) String() string {
+	ns := r.Namespace
+	if ns == "" {
+		ns = DefaultNamespaceName
+	}
+
+	return fmt.Sprintf("%s:%s/%s", strings.ToLower(r.Kind), strings.ToLower(ns), r.Name)
+}
+
+// Equal returns true if both references point to the same entity. References are compared case-insensitively.
+func (r EntityRef) Equal(other EntityRef) bool {
+	return strings.EqualFold(r.String(), other.String())
+}
+
+// Ref returns the reference to the entity.
+func (e *Entity) Ref() EntityRef {
+	return EntityRef{
+		Kind:      e.Kind,
+		Namespace: e.Metadata.Namespace,
+		Name:      e.Metadata.Name,
+	}
+}
+
+// Ref returns the reference to the target entity.
+func (t EntityRelationTarget) Ref() EntityRef {
+	return EntityRef{
+		Kind:      t.Kind,
+		Namespace: t.Namespace,
+		Name:      t.Name,
+	}
+}
+
+// Ref returns the reference to the target of the relation. It is taken from Target if set, and parsed from TargetRef otherwise.
+func (r EntityRelation) Ref() (EntityRef, error) {
+	if r.Target.Name != "" {
+		return r.Target.Ref(), nil
+	}
+
+	return ParseEntityRef(r.TargetRef, EntityRefDefaults{})
+}
+
+// parseOptionalRef parses a reference, returning nil if the reference is empty.
+func parseOptionalRef(ref string, kind string, ns string) (*EntityRef, error) {
+	if ref == "" {
+		return nil, nil
+	}
+
+	r, err := ParseEntityRef(ref, EntityRefDefaults{Kind: kind, Namespace: ns})
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// parseRefs parses a list of references.
+func parseRefs(refs []string, kind string, ns string) ([]EntityRef, error) {
+	if refs == nil {
+		return nil, nil
+	}
+
+	parsed := make([]EntityRef, 0, len(refs))
+	for _, ref := range refs {
+		r, err := ParseEntityRef(ref, EntityRefDefaults{Kind: kind, Namespace: ns})
+		if err != nil {
+			return nil, err
+		}
+
+		parsed = append(parsed, r)
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,139 @@
+package backstage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseEntityRef tests parsing of entity references.
+func TestParseEntityRef(t *testing.T) {
+	tests := []struct {
+		name      string
+		ref       string
+		defaults  EntityRefDefaults
+		expected  EntityRef
+		shouldErr bool
+	}{
+		{
+			name:     "full reference",
+			ref:      "component:payments/checkout",
+			expected: EntityRef{Kind: "component", Namespace: "payments", Name: "checkout"},
+		},
+		{
+			name:     "default namespace",
+			ref:      "Component:checkout",
+			expected: EntityRef{Kind: "Component", Namespace: "default", Name: "checkout"},
+		},
+		{
+			name:     "default kind",
+			ref:      "payments/team-a",
+			defaults: EntityRefDefaults{Kind: KindGroup},
+			expected: EntityRef{Kind: "Group", Namespace: "payments", Name: "team-a"},
+		},
+		{
+			name:     "default kind and namespace",
+			ref:      "team-a",
+			defaults: EntityRefDefaults{Kind: KindGroup, Namespace: "custom"},
+			expected: EntityRef{Kind: "Group", Namespace: "custom", Name: "team-a"},
+		},
+		{
+			name:     "kind in reference takes precedence",
+			ref:      "user:jane",
+			defaults: EntityRefDefaults{Kind: KindGroup},
+			expected: EntityRef{Kind: "user", Namespace: "default", Name: "jane"},
+		},
+		{
+			name:     "colon after slash belongs to name",
+			ref:      "ns/foo:bar",
+			defaults: EntityRefDefaults{Kind: KindComponent},
+			expected: EntityRef{Kind: "Component", Namespace: "ns", Name: "foo:bar"},
+		},
+		{name: "missing kind", ref: "team-a", shouldErr: true},
+		{name: "empty kind", ref: ":team-a", defaults: EntityRefDefaults{Kind: KindGroup}, shouldErr: true},
+		{name: "empty namespace", ref: "group:/team-a", shouldErr: true},
+		{name: "missing name", ref: "group:default/", shouldErr: true},
+		{name: "empty reference", ref: "", defaults: EntityRefDefaults{Kind: KindGroup}, shouldErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := ParseEntityRef(test.ref, test.defaults)
+			if test.shouldErr {
+				assert.Error(t, err, "Expected error but got nil")
+			} else {
+				assert.NoError(t, err, "Unexpected error")
+				assert.Equal(t, test.expected, actual, "Parsed reference should match")
+			}
+		})
+	}
+}
+
+// TestClientParseEntityRef tests that the client's default namespace is used when parsing references.
+func TestClientParseEntityRef(t *testing.T) {
+	c, _ := NewClient("", "custom", nil)
+
+	actual, err := c.ParseEntityRef("team-a", KindGroup)
+	assert.NoError(t, err, "Unexpected error")
+	assert.Equal(t, "group:custom/team-a", actual.String(), "Client default namespace should be used")
+}
+
+// TestEntityRefString tests the canonical string representation of references.
+func TestEntityRefString(t *testing.T) {
+	assert.Equal(t, "component:payments/Checkout", EntityRef{Kind: "Component", Namespace: "Payments", Name: "Checkout"}.String())
+	assert.Equal(t, "group:default/team-a", EntityRef{Kind: "Group", Name: "team-a"}.String())
+}
+
+// TestEntityRefEqual tests comparison of references.
+func TestEntityRefEqual(t *testing.T) {
+	a := EntityRef{Kind: "Component", Namespace: "default", Name: "Checkout"}
+
+	assert.True(t, a.Equal(EntityRef{Kind: "component", Name: "checkout"}), "References should be equal")
+	assert.False(t, a.Equal(EntityRef{Kind: "api", Name: "checkout"}), "References should not be equal")
+}
+
+// TestEntityRelationRef tests conversion of relation targets to references.
+func TestEntityRelationRef(t *testing.T) {
+	target := EntityRelationTarget{Kind: "group", Namespace: "default", Name: "guests"}
+	assert.Equal(t, EntityRef{Kind: "group", Namespace: "default", Name: "guests"}, target.Ref(), "Target reference should match")
+
+	actual, err := EntityRelation{Type: "ownedBy", Target: target}.Ref()
+	assert.NoError(t, err, "Unexpected error")
+	assert.Equal(t, target.Ref(), actual, "Relation reference should be taken from target")
+
+	actual, err = EntityRelation{Type: "ownedBy", TargetRef: "group:default/guests"}.Ref()
+	assert.NoError(t, err, "Unexpected error")
+	assert.Equal(t, target.Ref(), actual, "Relation reference should be parsed from target ref")
+}
+
+// TestSpecRefs tests parsing of references from typed specs.
+func TestSpecRefs(t *testing.T) {
+	spec := ComponentEntityV1alpha1Spec{
+		Owner:        "guests",
+		ProvidesApis: []string{"example-grpc-api", "api:other/other-api"},
+		DependsOn:    []string{"resource:db"},
+	}
+
+	owner, err := spec.OwnerRef("payments")
+	assert.NoError(t, err, "Unexpected error")
+	assert.Equal(t, "group:payments/guests", owner.String(), "Owner should default to group kind and entity namespace")
+
+	system, err := spec.SystemRef("")
+	assert.NoError(t, err, "Unexpected error")
+	assert.Nil(t, system, "Unset reference should be nil")
+
+	apis, err := spec.ProvidesApisRefs("")
+	assert.NoError(t, err, "Unexpected error")
+	assert.Equal(t, []EntityRef{{Kind: "API", Namespace: "default", Name: "example-grpc-api"}, {Kind: "api", Namespace: "other", Name: "other-api"}}, apis)
+
+	deps, err := spec.DependsOnRefs("")
+	assert.NoError(t, err, "Unexpected error")
+	assert.Equal(t, "resource:default/db", deps[0].String(), "Dependency should be parsed")
+
+	_, err = (&ComponentEntityV1alpha1Spec{DependsOn: []string{"db"}}).DependsOnRefs("")
+	assert.Error(t, err, "Dependency without kind should return an error")
+
+	members, err := (&UserEntityV1alpha1Spec{MemberOf: []string{"guests"}}).MemberOfRefs("")
+	assert.NoError(t, err, "Unexpected error")
+	assert.Equal(t, "group:default/guests", members[0].String(), "Membership should default to group kind")
+}
@@ -3,6 +3,7 @@ package backstage
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -188,6 +189,279 @@ func TestEntityServiceList_InvalidOrder(t *testing.T) {
 	assert.Error(t, err, "Get should return an error when the order is invalid")
 }
 
+// TestEntityServiceList_Pagination tests the retrieval of a list of entities with offset and limit.
+func TestEntityServiceList_Pagination(t *testing.T) {
+	const dataFile = "testdata/entities.json"
+
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("offset", "10").
+		MatchParam("limit", "5").
+		MatchParam("after", "cursor").
+		Reply(200).
+		File(dataFile)
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	s := newEntityService(newCatalogService(c))
+
+	_, resp, err := s.List(context.Background(), &ListEntityOptions{
+		Offset: 10,
+		Limit:  5,
+		After:  "cursor",
+	})
+	assert.NoError(t, err, "List should not return an error")
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "Response status code should be 200")
+}
+
+// TestEntityServiceListAll tests paging through entities using offsets.
+func TestEntityServiceListAll(t *testing.T) {
+	const dataFile = "testdata/entities.json"
+
+	var all []Entity
+	expectedData, _ := os.ReadFile(dataFile)
+	err := json.Unmarshal(expectedData, &all)
+
+	assert.FileExists(t, dataFile, "Test data file should exist")
+	assert.NoError(t, err, "Unmarshal should not return an error")
+
+	pageSize := len(all) - 1
+
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("filter", "kind=User").
+		MatchParam("limit", fmt.Sprint(pageSize)).
+		ParamPresent("offset").
+		Reply(200).
+		JSON(all[pageSize:])
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("filter", "kind=User").
+		MatchParam("limit", fmt.Sprint(pageSize)).
+		Reply(200).
+		JSON(all[:pageSize])
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	s := newEntityService(newCatalogService(c))
+
+	var expected, actual []string
+	for _, e := range all {
+		expected = append(expected, e.Metadata.UID)
+	}
+
+	var offsets []int
+	err = s.ListAll(context.Background(), &ListEntityOptions{Filters: []string{"kind=User"}}, pageSize, func(p *EntityPage[Entity]) error {
+		for _, e := range p.Entities {
+			actual = append(actual, e.Metadata.UID)
+		}
+		offsets = append(offsets, p.Offset)
+		return nil
+	})
+	assert.NoError(t, err, "ListAll should not return an error")
+	assert.Equal(t, expected, actual, "All entities should be returned")
+	assert.Equal(t, []int{0, pageSize}, offsets, "Page offsets should match")
+}
+
+// TestEntityServiceListAll_Cursor tests paging through entities using the cursor from the Link header.
+func TestEntityServiceListAll_Cursor(t *testing.T) {
+	const dataFile = "testdata/entities.json"
+
+	var all []Entity
+	expectedData, _ := os.ReadFile(dataFile)
+	_ = json.Unmarshal(expectedData, &all)
+
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("after", "next-page").
+		Reply(200).
+		JSON(all[1:2])
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("limit", "1").
+		Reply(200).
+		SetHeader("Link", `</api/catalog/entities?limit=1&after=next-page>; rel="next"`).
+		JSON(all[:1])
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	s := newEntityService(newCatalogService(c))
+
+	var cursors []string
+	err := s.ListAll(context.Background(), nil, 1, func(p *EntityPage[Entity]) error {
+		cursors = append(cursors, p.Next)
+		return nil
+	})
+	assert.NoError(t, err, "ListAll should not return an error")
+	assert.Equal(t, []string{"next-page", ""}, cursors, "Cursors should be taken from the Link header")
+}
+
+// TestEntityServiceListAll_OffsetIgnored tests that paging stops when the server ignores the offset and repeats a page.
+func TestEntityServiceListAll_OffsetIgnored(t *testing.T) {
+	const dataFile = "testdata/entities.json"
+
+	var all []Entity
+	expectedData, _ := os.ReadFile(dataFile)
+	_ = json.Unmarshal(expectedData, &all)
+
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("limit", fmt.Sprint(len(all))).
+		Times(2).
+		Reply(200).
+		JSON(all)
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	s := newEntityService(newCatalogService(c))
+
+	var pages int
+	err := s.ListAll(context.Background(), nil, len(all), func(p *EntityPage[Entity]) error {
+		pages++
+		return nil
+	})
+	assert.NoError(t, err, "ListAll should not return an error")
+	assert.Equal(t, 1, pages, "Repeated page should not be passed to the callback")
+	assert.True(t, gock.IsDone(), "Paging should stop after the repeated page")
+}
+
+// TestEntityServiceListAll_Fields tests paging through entities limited to fields without UIDs and names.
+func TestEntityServiceListAll_Fields(t *testing.T) {
+	page := func(owners ...string) []Entity {
+		var entities []Entity
+		for _, o := range owners {
+			entities = append(entities, Entity{Spec: map[string]interface{}{"owner": o}})
+		}
+		return entities
+	}
+
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("fields", "spec.owner").
+		MatchParam("offset", "2").
+		Reply(200).
+		JSON(page("team-c"))
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("fields", "spec.owner").
+		Reply(200).
+		JSON(page("team-a", "team-b"))
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	s := newEntityService(newCatalogService(c))
+
+	var owners []interface{}
+	err := s.ListAll(context.Background(), &ListEntityOptions{Fields: []string{"spec.owner"}}, 2, func(p *EntityPage[Entity]) error {
+		for _, e := range p.Entities {
+			owners = append(owners, e.Spec["owner"])
+		}
+		return nil
+	})
+	assert.NoError(t, err, "ListAll should not return an error")
+	assert.Equal(t, []interface{}{"team-a", "team-b", "team-c"}, owners, "All pages should be listed")
+	assert.True(t, gock.IsDone(), "All pages should be requested")
+}
+
+// TestEntityServiceListAll_CallbackError tests that paging stops when the callback returns an error.
+func TestEntityServiceListAll_CallbackError(t *testing.T) {
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		Reply(200).
+		File("testdata/entities.json")
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	s := newEntityService(newCatalogService(c))
+
+	expected := errors.New("stop")
+	err := s.ListAll(context.Background(), nil, 1, func(p *EntityPage[Entity]) error {
+		return expected
+	})
+	assert.ErrorIs(t, err, expected, "ListAll should return the callback error")
+}
+
+// TestEntityServiceStream tests iterating over entities fetched in pages.
+func TestEntityServiceStream(t *testing.T) {
+	const dataFile = "testdata/entities.json"
+
+	var all []Entity
+	expectedData, _ := os.ReadFile(dataFile)
+	_ = json.Unmarshal(expectedData, &all)
+
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("offset", "2").
+		Reply(200).
+		JSON(all[2:3])
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("limit", "2").
+		Reply(200).
+		JSON(all[:2])
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	s := newEntityService(newCatalogService(c))
+
+	var actual []string
+	for e, err := range s.Stream(context.Background(), nil, 2) {
+		assert.NoError(t, err, "Stream should not yield an error")
+		actual = append(actual, e.Metadata.Name)
+	}
+	assert.Equal(t, []string{all[0].Metadata.Name, all[1].Metadata.Name, all[2].Metadata.Name}, actual, "All entities should be yielded")
+}
+
+// TestWithKindFilter tests if the kind condition is added to every filter.
+func TestWithKindFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  *ListEntityOptions
+		expected []string
+	}{
+		{
+			name:     "no options",
+			options:  nil,
+			expected: []string{"kind=component"},
+		},
+		{
+			name:     "no filters",
+			options:  &ListEntityOptions{Fields: []string{"metadata.name"}},
+			expected: []string{"kind=component"},
+		},
+		{
+			name:     "multiple filters",
+			options:  &ListEntityOptions{Filters: []string{"spec.type=service", "spec.type=website,spec.lifecycle=production"}},
+			expected: []string{"kind=component,spec.type=service", "kind=component,spec.type=website,spec.lifecycle=production"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := withKindFilter(test.options, KindComponent)
+			assert.Equal(t, test.expected, actual.Filters, "Filters should be restricted to the kind")
+		})
+	}
+}
+
 // TestEntityServiceDelete tests the deletion of an entity.
 func TestEntityServiceDelete(t *testing.T) {
 	const uid = "uid"
@@ -208,6 +482,100 @@ func TestEntityServiceDelete(t *testing.T) {
 	assert.EqualValues(t, http.StatusNoContent, resp.StatusCode, "Response status code should match the one from the server")
 }
 
+// TestEntityServiceDeleteByRef tests the deletion of an entity identified by its reference.
+func TestEntityServiceDeleteByRef(t *testing.T) {
+	const dataFile = "testdata/entities_single.json"
+	const uid = "a1708238-d7d5-40ef-9d8e-bb24859c99a2"
+
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities/by-name/group/default/guests").
+		Reply(200).
+		File(dataFile)
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Delete(fmt.Sprintf("/catalog/entities/by-uid/%s", uid)).
+		Reply(http.StatusNoContent)
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	s := newEntityService(newCatalogService(c))
+
+	resp, err := s.DeleteByRef(context.Background(), "Group:guests", &DeleteEntityOptions{
+		Etag: "f0316ffdfb9e3b59dbcb87391a6f5e5174e925ed",
+	})
+	assert.NoError(t, err, "Delete should not return an error")
+	assert.EqualValues(t, http.StatusNoContent, resp.StatusCode, "Response status code should match the one from the server")
+	assert.True(t, gock.IsDone(), "Both lookup and deletion should be performed")
+}
+
+// TestEntityServiceDeleteByRef_Refused tests that deletion by reference is refused when the guards are not met.
+func TestEntityServiceDeleteByRef_Refused(t *testing.T) {
+	const dataFile = "testdata/entities_single.json"
+
+	tests := []struct {
+		name     string
+		options  *DeleteEntityOptions
+		expected error
+	}{
+		{
+			name:     "etag mismatch",
+			options:  &DeleteEntityOptions{Etag: "outdated"},
+			expected: ErrEtagMismatch,
+		},
+		{
+			name:     "not orphaned",
+			options:  &DeleteEntityOptions{RequireOrphan: true},
+			expected: ErrEntityNotOrphan,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			baseURL, _ := url.Parse("https://foo:1234/api")
+			defer gock.Off()
+			gock.New(baseURL.String()).
+				MatchHeader("Accept", "application/json").
+				Get("/catalog/entities/by-name/group/default/guests").
+				Reply(200).
+				File(dataFile)
+
+			c, _ := NewClient(baseURL.String(), "", nil)
+			s := newEntityService(newCatalogService(c))
+
+			_, err := s.DeleteByRef(context.Background(), "group:default/guests", test.options)
+			assert.ErrorIs(t, err, test.expected, "Delete should be refused with a matching error")
+		})
+	}
+}
+
+// TestEntityServiceDeleteByRef_NotFound tests that deletion by reference fails when the entity does not exist.
+func TestEntityServiceDeleteByRef_NotFound(t *testing.T) {
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities/by-name/component/default/missing").
+		Reply(http.StatusNotFound).
+		JSON(map[string]interface{}{"error": map[string]string{"name": "NotFoundError"}})
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	s := newEntityService(newCatalogService(c))
+
+	_, err := s.DeleteByRef(context.Background(), "component:default/missing", nil)
+	assert.ErrorIs(t, err, ErrEntityNotFound, "Delete should return not found error")
+}
+
+// TestEntityServiceDeleteByRef_InvalidRef tests that deletion by reference fails when the reference is invalid.
+func TestEntityServiceDeleteByRef_InvalidRef(t *testing.T) {
+	c, _ := NewClient("", "", nil)
+	s := newEntityService(newCatalogService(c))
+
+	_, err := s.DeleteByRef(context.Background(), "guests", nil)
+	assert.Error(t, err, "Delete should return an error when the reference is invalid")
+}
+
 // TestListEntityOrderString tests if list entity order string is correctly generated.
 func TestListEntityOrderString(t *testing.T) {
 	tests := []struct {
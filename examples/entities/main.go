@@ -46,6 +46,19 @@ func main() {
 		log.Printf("Component, location and API entities: %v", entities)
 	}
 
+	log.Println("Getting service components with GitHub project slug...")
+	if entities, _, err := c.Catalog.Entities.List(context.Background(), &backstage.ListEntityOptions{
+		Filters: backstage.Filter().
+			Kind("component").
+			Eq("spec.type", "service").
+			Exists("metadata.annotations.github.com/project-slug").
+			MustBuild(),
+	}); err != nil {
+		log.Fatal(err)
+	} else {
+		log.Printf("Service components: %v", entities)
+	}
+
 	log.Println("Getting specific component entity by UID...")
 	if entity, _, err := c.Catalog.Entities.Get(context.Background(), "06f15cc8-b6b4-4e44-a9bd-1579029f8fb7"); err != nil {
 		log.Fatal(err)
@@ -51,7 +51,7 @@ func main() {
 	}
 
 	log.Println("Listing all locations...")
-	if locations, _, err := c.Catalog.Locations.List(context.Background()); err != nil {
+	if locations, _, err := c.Catalog.Locations.ListFlat(context.Background()); err != nil {
 		log.Fatal(err)
 	} else {
 		if len(locations) == 0 {
@@ -0,0 +1,181 @@
+package backstage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// FieldPath is a dot-separated path to an entity field, e.g. "metadata.name". It can be used to limit the fields returned
+// by the catalog (see ListEntityOptions.Fields) and to read fields of a PartialEntity.
+type FieldPath string
+
+// Field paths of the fields common to entities of all kinds.
+const (
+	FieldApiVersion          FieldPath = "apiVersion"
+	FieldKind                FieldPath = "kind"
+	FieldMetadata            FieldPath = "metadata"
+	FieldMetadataUID         FieldPath = "metadata.uid"
+	FieldMetadataEtag        FieldPath = "metadata.etag"
+	FieldMetadataName        FieldPath = "metadata.name"
+	FieldMetadataNamespace   FieldPath = "metadata.namespace"
+	FieldMetadataTitle       FieldPath = "metadata.title"
+	FieldMetadataDescription FieldPath = "metadata.description"
+	FieldMetadataLabels      FieldPath = "metadata.labels"
+	FieldMetadataAnnotations FieldPath = "metadata.annotations"
+	FieldMetadataTags        FieldPath = "metadata.tags"
+	FieldMetadataLinks       FieldPath = "metadata.links"
+	FieldSpec                FieldPath = "spec"
+	FieldSpecType            FieldPath = "spec.type"
+	FieldSpecLifecycle       FieldPath = "spec.lifecycle"
+	FieldSpecOwner           FieldPath = "spec.owner"
+	FieldSpecSystem          FieldPath = "spec.system"
+	FieldSpecDomain          FieldPath = "spec.domain"
+	FieldSpecDependsOn       FieldPath = "spec.dependsOn"
+	FieldSpecProvidesApis    FieldPath = "spec.providesApis"
+	FieldSpecConsumesApis    FieldPath = "spec.consumesApis"
+	FieldSpecMemberOf        FieldPath = "spec.memberOf"
+	FieldSpecParent          FieldPath = "spec.parent"
+	FieldSpecChildren        FieldPath = "spec.children"
+	FieldSpecMembers         FieldPath = "spec.members"
+	FieldRelations           FieldPath = "relations"
+	FieldStatus              FieldPath = "status"
+)
+
+// PartialEntity is an entity decoded from a response limited to a set of fields. Unlike Entity, it tracks which fields were
+// returned, so that a field that is absent can be told apart from a field that is empty.
+type PartialEntity struct {
+	data map[string]interface{}
+}
+
+// Fields converts field paths to the form used by ListEntityOptions.Fields.
+func Fields(paths ...FieldPath) []string {
+	fields := make([]string, 0, len(paths))
+	for _, p := range paths {
+		fields = append(fields, string(p))
+	}
+
+	return fields
+}
+
+// ListPartial returns a list of entities, decoded as partial entities. It is intended to be used with ListEntityOptions.Fields.
+func (s *entityService) ListPartial(ctx context.Context, options *ListEntityOptions) ([]PartialEntity, *http.Response, error) {
+	return list[PartialEntity](ctx, s, options)
+}
+
+// ListInto returns a list of entities, decoded into the caller-provided type T. It is intended to be used with
+// ListEntityOptions.Fields, decoding only the selected fields into a purpose-built struct, e.g.:
+//
+//	type owned struct {
+//	        Metadata struct{ Name string } `json:"metadata"`
+//	        Spec     struct{ Owner *string } `json:"spec"`
+//	}
+//	entities, resp, err := backstage.ListInto[owned](ctx, client.Catalog.Entities, &backstage.ListEntityOptions{
+//	        Fields: backstage.Fields(backstage.FieldMetadataName, backstage.FieldSpecOwner),
+//	})
+func ListInto[T any](ctx context.Context, s *entityService, options *ListEntityOptions) ([]T, *http.Response, error) {
+	return list[T](ctx, s, options)
+}
+
+// UnmarshalJSON decodes the partial entity.
+func (e *PartialEntity) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &e.data)
+}
+
+// MarshalJSON encodes the partial entity, containing only the returned fields.
+func (e PartialEntity) MarshalJSON() ([]byte, error) {
+	if e.data == nil {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(e.data)
+}
+
+// Has returns true if the field was returned.
+func (e PartialEntity) Has(path FieldPath) bool {
+	_, ok := e.Get(path)
+	return ok
+}
+
+// Get returns the value of the field, as decoded from JSON, and whether it was returned.
+func (e PartialEntity) Get(path FieldPath) (interface{}, bool) {
+	return lookupPath(e.data, strings.Split(string(path), "."))
+}
+
+// String returns the value of the field, if it was returned and is a string.
+func (e PartialEntity) String(path FieldPath) (string, bool) {
+	v, ok := e.Get(path)
+	if !ok {
+		return "", false
+	}
+
+	s, ok := v.(string)
+
+	return s, ok
+}
+
+// Strings returns the value of the field, if it was returned and is an array of strings.
+func (e PartialEntity) Strings(path FieldPath) ([]string, bool) {
+	v, ok := e.Get(path)
+	if !ok {
+		return nil, false
+	}
+
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	strs := make([]string, 0, len(items))
+	for _, i := range items {
+		s, ok := i.(string)
+		if !ok {
+			return nil, false
+		}
+
+		strs = append(strs, s)
+	}
+
+	return strs, true
+}
+
+// Entity decodes the partial entity into Entity, leaving the fields that were not returned empty.
+func (e PartialEntity) Entity() (Entity, error) {
+	var entity Entity
+
+	data, err := e.MarshalJSON()
+	if err != nil {
+		return entity, err
+	}
+
+	err = json.Unmarshal(data, &entity)
+
+	return entity, err
+}
+
+// lookupPath returns the value at the dot-separated path segments. Since map keys (e.g. annotation keys) can contain dots
+// themselves, the longest key matching the leading segments is tried first.
+func lookupPath(v interface{}, segments []string) (interface{}, bool) {
+	if len(segments) == 0 {
+		return v, true
+	}
+
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	for i := len(segments); i > 0; i-- {
+		next, ok := m[strings.Join(segments[:i], ".")]
+		if !ok {
+			continue
+		}
+
+		if found, ok := lookupPath(next, segments[i:]); ok {
+			return found, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,108 @@
+package backstage
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFields tests conversion of field paths to ListEntityOptions.Fields.
+func TestFields(t *testing.T) {
+	actual := Fields(FieldMetadataName, FieldSpecOwner, FieldRelations)
+	assert.Equal(t, []string{"metadata.name", "spec.owner", "relations"}, actual, "Fields should match")
+}
+
+// TestEntityServiceListPartial tests the retrieval of a list of partial entities.
+func TestEntityServiceListPartial(t *testing.T) {
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("fields", "metadata.name,spec.owner,metadata.annotations").
+		Reply(200).
+		JSON([]map[string]interface{}{
+			{
+				"metadata": map[string]interface{}{
+					"name":        "foo",
+					"annotations": map[string]string{"backstage.io/techdocs-ref": "dir:."},
+				},
+				"spec": map[string]interface{}{"owner": ""},
+			},
+			{
+				"metadata": map[string]interface{}{"name": "bar"},
+			},
+		})
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+
+	actual, _, err := c.Catalog.Entities.ListPartial(context.Background(), &ListEntityOptions{
+		Fields: Fields(FieldMetadataName, FieldSpecOwner, FieldMetadataAnnotations),
+	})
+	assert.NoError(t, err, "ListPartial should not return an error")
+	assert.Len(t, actual, 2, "All entities should be returned")
+
+	owner, ok := actual[0].String(FieldSpecOwner)
+	assert.True(t, ok, "Empty owner should be returned")
+	assert.Equal(t, "", owner, "Owner should be empty")
+	assert.False(t, actual[1].Has(FieldSpecOwner), "Missing owner should not be returned")
+	assert.False(t, actual[1].Has(FieldSpec), "Missing spec should not be returned")
+
+	ref, ok := actual[0].String("metadata.annotations.backstage.io/techdocs-ref")
+	assert.True(t, ok, "Annotation with dots in its key should be returned")
+	assert.Equal(t, "dir:.", ref, "Annotation value should match")
+
+	entity, err := actual[1].Entity()
+	assert.NoError(t, err, "Entity should not return an error")
+	assert.Equal(t, "bar", entity.Metadata.Name, "Entity should be decoded")
+}
+
+// TestListInto tests the retrieval of a list of entities decoded into a caller-provided type.
+func TestListInto(t *testing.T) {
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		Reply(200).
+		File("testdata/entities_fields.json")
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+
+	type named struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Spec *struct {
+			Owner *string `json:"owner"`
+		} `json:"spec"`
+	}
+
+	actual, _, err := ListInto[named](context.Background(), c.Catalog.Entities, &ListEntityOptions{
+		Fields: Fields(FieldMetadataName),
+	})
+	assert.NoError(t, err, "ListInto should not return an error")
+	assert.Equal(t, "example-website", actual[0].Metadata.Name, "Selected field should be decoded")
+	assert.Nil(t, actual[0].Spec, "Not selected field should be absent")
+}
+
+// TestPartialEntityStrings tests reading array fields of a partial entity.
+func TestPartialEntityStrings(t *testing.T) {
+	var e PartialEntity
+	err := json.Unmarshal([]byte(`{"metadata": {"tags": ["go", "java"], "name": "foo"}, "spec": {"dependsOn": [1]}}`), &e)
+	assert.NoError(t, err, "Unmarshal should not return an error")
+
+	tags, ok := e.Strings(FieldMetadataTags)
+	assert.True(t, ok, "Tags should be returned")
+	assert.Equal(t, []string{"go", "java"}, tags, "Tags should match")
+
+	_, ok = e.Strings(FieldSpecDependsOn)
+	assert.False(t, ok, "Non-string array should not be returned")
+
+	_, ok = e.Strings(FieldMetadataName)
+	assert.False(t, ok, "Non-array field should not be returned")
+}
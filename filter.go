@@ -0,0 +1,220 @@
+package backstage
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FilterExpression is a set of conditions that can be rendered into ListEntityOptions.Filters.
+type FilterExpression interface {
+	// Build returns the filters, or an error if any of the conditions is invalid.
+	Build() ([]string, error)
+}
+
+// FilterBuilder builds a single filter, matching entities that satisfy all of its conditions. It is created with Filter.
+type FilterBuilder struct {
+	conditions []filterCondition
+	err        error
+}
+
+// FilterSet combines filters, matching entities that satisfy any of them. It is created with Or.
+type FilterSet struct {
+	filters []FilterExpression
+}
+
+// filterCondition is a single condition of a filter. A condition without values checks only for the existence of the field.
+type filterCondition struct {
+	path   string
+	values []string
+}
+
+// filterRoots contains the top-level entity fields that can be used in filters.
+var filterRoots = map[string]bool{
+	"apiversion": true,
+	"kind":       true,
+	"metadata":   true,
+	"spec":       true,
+	"relations":  true,
+	"status":     true,
+}
+
+// Filter returns a new filter builder, e.g.:
+//
+//	filters, err := Filter().Kind("component").Eq("spec.type", "service").Build()
+func Filter() *FilterBuilder {
+	return &FilterBuilder{}
+}
+
+// Or returns a set of filters, matching entities that satisfy any of them.
+func Or(filters ...FilterExpression) *FilterSet {
+	return &FilterSet{filters: filters}
+}
+
+// Kind adds a condition matching entities of the given kind.
+func (f *FilterBuilder) Kind(kind string) *FilterBuilder {
+	return f.Eq("kind", kind)
+}
+
+// Namespace adds a condition matching entities in the given namespace.
+func (f *FilterBuilder) Namespace(ns string) *FilterBuilder {
+	return f.Eq("metadata.namespace", ns)
+}
+
+// Eq adds a condition matching entities whose field, identified by a dot-separated path, equals the value. Matching is
+// case-insensitive and, for array fields, matches if any of the items equals the value.
+func (f *FilterBuilder) Eq(path string, value string) *FilterBuilder {
+	return f.In(path, value)
+}
+
+// In adds a condition matching entities whose field, identified by a dot-separated path, equals any of the values.
+func (f *FilterBuilder) In(path string, values ...string) *FilterBuilder {
+	if len(values) == 0 {
+		f.setErr(fmt.Errorf("no values provided for filter field: %s", path))
+		return f
+	}
+
+	return f.add(path, values)
+}
+
+// Exists adds a condition matching entities that have the field, identified by a dot-separated path, set to any value.
+func (f *FilterBuilder) Exists(path string) *FilterBuilder {
+	return f.add(path, nil)
+}
+
+// Build returns the filter as a single ListEntityOptions.Filters item, or an error if any of the conditions is invalid.
+func (f *FilterBuilder) Build() ([]string, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	if len(f.conditions) == 0 {
+		return nil, errors.New("filter has no conditions")
+	}
+
+	var parts []string
+	for _, c := range f.conditions {
+		if len(c.values) == 0 {
+			parts = append(parts, c.path)
+			continue
+		}
+
+		for _, v := range c.values {
+			parts = append(parts, c.path+"="+v)
+		}
+	}
+
+	return []string{strings.Join(parts, ",")}, nil
+}
+
+// MustBuild is like Build, but panics if any of the conditions is invalid.
+func (f *FilterBuilder) MustBuild() []string {
+	return mustBuild(f)
+}
+
+// Build returns all the filters of the set, or an error if any of them is invalid.
+func (s *FilterSet) Build() ([]string, error) {
+	if len(s.filters) == 0 {
+		return nil, errors.New("filter set has no filters")
+	}
+
+	var filters []string
+	for _, f := range s.filters {
+		built, err := f.Build()
+		if err != nil {
+			return nil, err
+		}
+
+		filters = append(filters, built...)
+	}
+
+	return filters, nil
+}
+
+// MustBuild is like Build, but panics if any of the filters is invalid.
+func (s *FilterSet) MustBuild() []string {
+	return mustBuild(s)
+}
+
+// add validates and adds a condition to the filter.
+func (f *FilterBuilder) add(path string, values []string) *FilterBuilder {
+	if err := validateFilterPath(path); err != nil {
+		f.setErr(err)
+		return f
+	}
+
+	for _, c := range f.conditions {
+		if strings.EqualFold(c.path, path) {
+			// Repeating a field within a filter makes the catalog match any of its values, not all of them.
+			f.setErr(fmt.Errorf("filter field used more than once: %s (use In to match any of several values)", path))
+			return f
+		}
+	}
+
+	for _, v := range values {
+		if err := validateFilterValue(v); err != nil {
+			f.setErr(fmt.Errorf("invalid value for filter field %s: %w", path, err))
+			return f
+		}
+	}
+
+	f.conditions = append(f.conditions, filterCondition{path: path, values: values})
+
+	return f
+}
+
+// setErr records the first error encountered while building the filter.
+func (f *FilterBuilder) setErr(err error) {
+	if f.err == nil {
+		f.err = err
+	}
+}
+
+// validateFilterPath checks that the path is a dot-separated path into one of the top-level entity fields.
+func validateFilterPath(path string) error {
+	if path == "" {
+		return errors.New("filter field cannot be empty")
+	}
+
+	if strings.ContainsAny(path, "=,") || strings.TrimSpace(path) != path {
+		return fmt.Errorf("invalid filter field: %s", path)
+	}
+
+	root, _, _ := strings.Cut(path, ".")
+	if !filterRoots[strings.ToLower(root)] {
+		return fmt.Errorf("unknown filter field: %s", path)
+	}
+
+	for _, segment := range strings.Split(path, ".") {
+		if segment == "" {
+			return fmt.Errorf("invalid filter field: %s", path)
+		}
+	}
+
+	return nil
+}
+
+// validateFilterValue checks that the value can be represented in a filter. The catalog splits conditions on commas and
+// trims surrounding whitespace without any escaping mechanism, so such values cannot be matched exactly. An equals sign is
+// safe, since only the first one separates the field from the value.
+func validateFilterValue(value string) error {
+	if strings.Contains(value, ",") {
+		return fmt.Errorf("value cannot contain a comma: %q", value)
+	}
+
+	if strings.TrimSpace(value) != value {
+		return fmt.Errorf("value cannot start or end with whitespace: %q", value)
+	}
+
+	return nil
+}
+
+// mustBuild builds the filter expression, panicking on error.
+func mustBuild(f FilterExpression) []string {
+	filters, err := f.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	return filters
+}
@@ -0,0 +1,375 @@
+package backstage
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// EntityMatcher evaluates catalog filters locally, with the semantics of the "filter" parameter of the catalog API:
+//
+//   - Keys are dot-separated paths into the entity, e.g. "spec.type" or "metadata.annotations.backstage.io/orphan", and
+//     are matched case-insensitively, as are the values.
+//   - Array fields match if any of their items match; objects in arrays are addressed by the path of the array, e.g.
+//     "metadata.links.url".
+//   - "relations.<type>" matches the references to the targets of the relations of the type, e.g.
+//     "relations.ownedBy=group:default/team-a".
+//   - A key without a value matches entities that have the field.
+//   - Conditions of a filter must all match, except that conditions repeating the same key match if any of their values
+//     match. An entity matches the matcher if it matches any of the filters.
+type EntityMatcher struct {
+	filters []compiledFilter
+}
+
+// compiledFilter is a parsed filter, with conditions keyed by the lowercase key. A condition without values checks only for
+// the existence of the field.
+type compiledFilter struct {
+	keys       []string
+	conditions map[string][]string
+}
+
+// entityData is an entity decoded into generic JSON values, used to project fields locally.
+type entityData map[string]interface{}
+
+// entitySearch contains the lowercase values of an entity, keyed by the lowercase paths of the fields, as indexed by the
+// catalog for filtering and ordering.
+type entitySearch map[string][]string
+
+// indexedEntity is an entity prepared for local evaluation of filters, order and fields.
+type indexedEntity struct {
+	entity Entity
+	data   entityData
+	search entitySearch
+}
+
+// NewEntityMatcher returns a matcher of the filters, as used by ListEntityOptions.Filters. A matcher without filters matches
+// all entities.
+func NewEntityMatcher(filters []string) (*EntityMatcher, error) {
+	compiled, err := compileFilters(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EntityMatcher{filters: compiled}, nil
+}
+
+// Match returns true if the entity matches any of the filters.
+func (m *EntityMatcher) Match(e Entity) (bool, error) {
+	indexed, err := newIndexedEntity(e)
+	if err != nil {
+		return false, err
+	}
+
+	return m.match(indexed.search), nil
+}
+
+// FilterEntities applies the options to the entities locally, as the catalog would: it returns the entities matching the
+// filters, ordered, paginated by offset and limit, and limited to the fields. Cursors are not supported.
+func FilterEntities(entities []Entity, options *ListEntityOptions) ([]Entity, error) {
+	indexed := make([]*indexedEntity, 0, len(entities))
+	for _, e := range entities {
+		i, err := newIndexedEntity(e)
+		if err != nil {
+			return nil, err
+		}
+
+		indexed = append(indexed, i)
+	}
+
+	return queryEntities(indexed, options)
+}
+
+// match returns true if the indexed values match any of the filters, or if there are no filters.
+func (m *EntityMatcher) match(search entitySearch) bool {
+	return matchFilters(m.filters, search)
+}
+
+// compileFilters parses the filters, as used by ListEntityOptions.Filters, for local evaluation with the semantics of the
+// catalog API.
+func compileFilters(filters []string) ([]compiledFilter, error) {
+	var compiled []compiledFilter
+
+	for _, f := range filters {
+		filter := compiledFilter{conditions: map[string][]string{}}
+		for _, c := range strings.Split(f, ",") {
+			if strings.TrimSpace(c) == "" {
+				continue
+			}
+
+			key, value, hasValue := strings.Cut(c, "=")
+			key = strings.ToLower(strings.TrimSpace(key))
+			if key == "" {
+				return nil, fmt.Errorf("invalid filter: %s", f)
+			}
+
+			if _, ok := filter.conditions[key]; !ok {
+				filter.keys = append(filter.keys, key)
+				filter.conditions[key] = nil
+			}
+
+			if hasValue {
+				filter.conditions[key] = append(filter.conditions[key], strings.ToLower(strings.TrimSpace(value)))
+			}
+		}
+
+		if len(filter.keys) == 0 {
+			return nil, fmt.Errorf("invalid filter: %q", f)
+		}
+
+		compiled = append(compiled, filter)
+	}
+
+	return compiled, nil
+}
+
+// matchFilters returns true if the indexed values match any of the filters, or if there are no filters.
+func matchFilters(filters []compiledFilter, search entitySearch) bool {
+	if len(filters) == 0 {
+		return true
+	}
+
+	for _, f := range filters {
+		if f.match(search) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// match returns true if the indexed values match all conditions of the filter.
+func (f compiledFilter) match(search entitySearch) bool {
+	for _, key := range f.keys {
+		found, ok := search[key]
+		if !ok {
+			return false
+		}
+
+		if want := f.conditions[key]; len(want) > 0 && !slices.ContainsFunc(found, func(v string) bool {
+			return slices.Contains(want, v)
+		}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// queryEntities applies the options to the indexed entities.
+func queryEntities(entities []*indexedEntity, options *ListEntityOptions) ([]Entity, error) {
+	opts := ListEntityOptions{}
+	if options != nil {
+		opts = *options
+	}
+
+	if opts.After != "" {
+		return nil, fmt.Errorf("cursors are not supported by local queries")
+	}
+
+	filters, err := compileFilters(opts.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []*indexedEntity
+	for _, e := range entities {
+		if matchFilters(filters, e.search) {
+			matched = append(matched, e)
+		}
+	}
+
+	if err := sortIndexed(matched, opts.Order); err != nil {
+		return nil, err
+	}
+
+	matched = matched[min(opts.Offset, len(matched)):]
+	if opts.Limit > 0 {
+		matched = matched[:min(opts.Limit, len(matched))]
+	}
+
+	result := make([]Entity, 0, len(matched))
+	for _, e := range matched {
+		if len(opts.Fields) == 0 {
+			result = append(result, e.entity)
+			continue
+		}
+
+		var projected Entity
+		if err := decodeEntityData(projectFields(e.data, opts.Fields), &projected); err != nil {
+			return nil, err
+		}
+
+		result = append(result, projected)
+	}
+
+	return result, nil
+}
+
+// newIndexedEntity returns the entity prepared for local evaluation.
+func newIndexedEntity(e Entity) (*indexedEntity, error) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	var data entityData
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+
+	return &indexedEntity{entity: e, data: data, search: newEntitySearch(data)}, nil
+}
+
+// newEntitySearch returns the values of the entity indexed for filtering and ordering. Relations are indexed by their type,
+// and the status is not indexed.
+func newEntitySearch(data entityData) entitySearch {
+	search := entitySearch{}
+	for k, v := range data {
+		if k == "relations" || k == "status" {
+			continue
+		}
+
+		search.add(strings.ToLower(k), v)
+	}
+
+	relations, _ := data["relations"].([]interface{})
+	for _, r := range relations {
+		r, _ := r.(map[string]interface{})
+		t, _ := r["type"].(string)
+		target, _ := r["targetRef"].(string)
+		if t != "" && target != "" {
+			search.add("relations."+strings.ToLower(t), target)
+		}
+	}
+
+	if _, ok := search["metadata.namespace"]; !ok {
+		search.add("metadata.namespace", DefaultNamespaceName)
+	}
+
+	return search
+}
+
+// add indexes the value at the key, traversing objects and arrays.
+func (s entitySearch) add(key string, v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			s.add(key+"."+strings.ToLower(k), child)
+		}
+	case []interface{}:
+		for _, item := range v {
+			s.add(key, item)
+		}
+	case string:
+		s[key] = append(s[key], strings.ToLower(v))
+	case bool, float64:
+		s[key] = append(s[key], fmt.Sprint(v))
+	}
+}
+
+// sortIndexed orders the entities by the fields of the order, comparing their lowest indexed values. Entities missing the
+// field are ordered last, regardless of the direction.
+func sortIndexed(entities []*indexedEntity, order []ListEntityOrder) error {
+	for _, o := range order {
+		if _, err := o.string(); err != nil {
+			return err
+		}
+	}
+
+	slices.SortStableFunc(entities, func(a, b *indexedEntity) int {
+		for _, o := range order {
+			key := strings.ToLower(o.Field)
+			va, vb := a.search[key], b.search[key]
+			switch {
+			case len(va) == 0 && len(vb) == 0:
+				continue
+			case len(va) == 0:
+				return 1
+			case len(vb) == 0:
+				return -1
+			}
+
+			c := strings.Compare(slices.Min(va), slices.Min(vb))
+			if o.Direction == OrderDescending {
+				c = -c
+			}
+
+			if c != 0 {
+				return c
+			}
+		}
+
+		return 0
+	})
+
+	return nil
+}
+
+// projectFields returns the entity limited to the fields. All fields are returned if none are specified.
+func projectFields(data entityData, fields []string) entityData {
+	if len(fields) == 0 {
+		return data
+	}
+
+	projected := entityData{}
+	for _, f := range fields {
+		for _, field := range strings.Split(f, ",") {
+			if keys, v, ok := lookupKeys(map[string]interface{}(data), strings.Split(strings.TrimSpace(field), ".")); ok {
+				setPath(projected, keys, v)
+			}
+		}
+	}
+
+	return projected
+}
+
+// lookupKeys is like lookupPath, but also returns the keys of the objects leading to the value, which can contain dots.
+func lookupKeys(v interface{}, segments []string) ([]string, interface{}, bool) {
+	if len(segments) == 0 {
+		return nil, v, true
+	}
+
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, nil, false
+	}
+
+	for i := len(segments); i > 0; i-- {
+		key := strings.Join(segments[:i], ".")
+		next, ok := m[key]
+		if !ok {
+			continue
+		}
+
+		if keys, found, ok := lookupKeys(next, segments[i:]); ok {
+			return append([]string{key}, keys...), found, true
+		}
+	}
+
+	return nil, nil, false
+}
+
+// setPath sets the value at the path of keys, creating intermediate objects as needed.
+func setPath(m map[string]interface{}, keys []string, v interface{}) {
+	for _, s := range keys[:len(keys)-1] {
+		next, ok := m[s].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			m[s] = next
+		}
+		m = next
+	}
+
+	m[keys[len(keys)-1]] = v
+}
+
+// decodeEntityData decodes generic JSON values into the entity.
+func decodeEntityData(data entityData, e *Entity) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, e)
+}
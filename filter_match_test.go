@@ -0,0 +1,111 @@
+package backstage
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEntityMatcher tests local evaluation of filters against the entity returned by the catalog in the filter test data.
+func TestEntityMatcher(t *testing.T) {
+	const dataFile = "testdata/entities_filter.json"
+
+	var entities []Entity
+	data, _ := os.ReadFile(dataFile)
+	err := json.Unmarshal(data, &entities)
+
+	assert.FileExists(t, dataFile, "Test data file should exist")
+	assert.NoError(t, err, "Unmarshal should not return an error")
+
+	tests := []struct {
+		name     string
+		filters  []string
+		expected bool
+	}{
+		{name: "no filters", filters: nil, expected: true},
+		{name: "kind", filters: []string{"kind=User"}, expected: true},
+		{name: "kind in other case", filters: []string{"KIND=user"}, expected: true},
+		{name: "other kind", filters: []string{"kind=Group"}, expected: false},
+		{name: "name", filters: []string{"metadata.name=guest"}, expected: true},
+		{name: "namespace", filters: []string{"metadata.namespace=default"}, expected: true},
+		{name: "whitespace", filters: []string{" kind = User , metadata.name = guest "}, expected: true},
+		{name: "array item", filters: []string{"spec.memberOf=guests"}, expected: true},
+		{name: "array item in other case", filters: []string{"spec.memberof=GUESTS"}, expected: true},
+		{name: "missing array item", filters: []string{"spec.memberOf=admins"}, expected: false},
+		{name: "relation", filters: []string{"relations.memberOf=group:default/guests"}, expected: true},
+		{name: "relation in other case", filters: []string{"relations.MEMBEROF=Group:Default/Guests"}, expected: true},
+		{name: "other relation", filters: []string{"relations.ownerOf=template:default/example-nodejs-template"}, expected: true},
+		{name: "relation target of other type", filters: []string{"relations.ownedBy=group:default/guests"}, expected: false},
+		{name: "relation exists", filters: []string{"relations.memberOf"}, expected: true},
+		{name: "relation does not exist", filters: []string{"relations.ownedBy"}, expected: false},
+		{name: "relations are not indexed as fields", filters: []string{"relations.type=memberOf"}, expected: false},
+		{
+			name:     "annotation",
+			filters:  []string{"metadata.annotations.backstage.io/managed-by-location=file:/private/tmp/back/examples/org.yaml"},
+			expected: true,
+		},
+		{name: "annotation exists", filters: []string{"metadata.annotations.backstage.io/managed-by-origin-location"}, expected: true},
+		{name: "annotation does not exist", filters: []string{"metadata.annotations.backstage.io/orphan"}, expected: false},
+		{name: "field does not exist", filters: []string{"metadata.labels"}, expected: false},
+		{name: "object is not a value", filters: []string{"spec"}, expected: false},
+		{name: "all conditions", filters: []string{"kind=User,metadata.name=guest"}, expected: true},
+		{name: "not all conditions", filters: []string{"kind=User,metadata.name=admin"}, expected: false},
+		{name: "repeated key", filters: []string{"kind=Group,kind=User"}, expected: true},
+		{name: "any filter", filters: []string{"kind=Group", "metadata.name=guest"}, expected: true},
+		{name: "no filter", filters: []string{"kind=Group", "kind=API"}, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, err := NewEntityMatcher(test.filters)
+			assert.NoError(t, err, "NewEntityMatcher should not return an error")
+
+			actual, err := m.Match(entities[0])
+			assert.NoError(t, err, "Match should not return an error")
+			assert.Equal(t, test.expected, actual, "Match should follow the catalog filter semantics")
+		})
+	}
+}
+
+// TestNewEntityMatcher_Invalid tests that invalid filters are rejected.
+func TestNewEntityMatcher_Invalid(t *testing.T) {
+	_, err := NewEntityMatcher([]string{"kind=User,=guest"})
+	assert.EqualError(t, err, "invalid filter: kind=User,=guest", "Condition without key should be rejected")
+
+	_, err = NewEntityMatcher([]string{" , "})
+	assert.EqualError(t, err, `invalid filter: " , "`, "Filter without conditions should be rejected")
+}
+
+// TestFilterEntities tests local filtering, ordering and projection of entities.
+func TestFilterEntities(t *testing.T) {
+	const dataFile = "testdata/entities.json"
+
+	var entities []Entity
+	data, _ := os.ReadFile(dataFile)
+	err := json.Unmarshal(data, &entities)
+	assert.NoError(t, err, "Unmarshal should not return an error")
+
+	actual, err := FilterEntities(entities, &ListEntityOptions{
+		Filters: []string{"relations.ownedBy=group:default/guests", "kind=user"},
+		Order: []ListEntityOrder{
+			{Direction: OrderAscending, Field: "spec.type"},
+			{Direction: OrderDescending, Field: "metadata.name"},
+		},
+		Fields: []string{"kind", "metadata.name"},
+	})
+
+	var names []string
+	for _, e := range actual {
+		names = append(names, e.Kind+":"+e.Metadata.Name)
+		assert.Empty(t, e.Metadata.UID, "Fields other than selected should not be returned")
+	}
+
+	assert.NoError(t, err, "FilterEntities should not return an error")
+	assert.Equal(t, []string{"API:example-grpc-api", "Component:example-website", "User:guest", "System:examples"}, names,
+		"Entities should be filtered and ordered, with entities missing the order field last")
+
+	_, err = FilterEntities(entities, &ListEntityOptions{Order: []ListEntityOrder{{Direction: "up", Field: "kind"}}})
+	assert.EqualError(t, err, "invalid order direction: up", "Invalid order should be rejected")
+}
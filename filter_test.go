@@ -0,0 +1,87 @@
+package backstage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFilterBuild tests rendering of filters into ListEntityOptions.Filters items.
+func TestFilterBuild(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   FilterExpression
+		expected []string
+	}{
+		{
+			name:     "single condition",
+			filter:   Filter().Kind("component"),
+			expected: []string{"kind=component"},
+		},
+		{
+			name: "all conditions",
+			filter: Filter().
+				Kind("component").
+				Eq("spec.type", "service").
+				Exists("metadata.annotations.github.com/project-slug"),
+			expected: []string{"kind=component,spec.type=service,metadata.annotations.github.com/project-slug"},
+		},
+		{
+			name:     "any of values",
+			filter:   Filter().In("kind", "component", "api").Namespace("default"),
+			expected: []string{"kind=component,kind=api,metadata.namespace=default"},
+		},
+		{
+			name:     "value with equals sign",
+			filter:   Filter().Eq("metadata.annotations.acme.com/query", "a=b"),
+			expected: []string{"metadata.annotations.acme.com/query=a=b"},
+		},
+		{
+			name:     "any of filters",
+			filter:   Or(Filter().Kind("component"), Filter().Kind("api").Eq("spec.type", "grpc")),
+			expected: []string{"kind=component", "kind=api,spec.type=grpc"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := test.filter.Build()
+			assert.NoError(t, err, "Build should not return an error")
+			assert.Equal(t, test.expected, actual, "Filters should match")
+		})
+	}
+}
+
+// TestFilterBuild_Invalid tests that invalid filters are rejected.
+func TestFilterBuild_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter FilterExpression
+	}{
+		{name: "no conditions", filter: Filter()},
+		{name: "no filters", filter: Or()},
+		{name: "empty field", filter: Filter().Eq("", "foo")},
+		{name: "unknown root field", filter: Filter().Eq("owner", "foo")},
+		{name: "empty path segment", filter: Filter().Eq("spec..owner", "foo")},
+		{name: "field with equals sign", filter: Filter().Exists("spec.owner=foo")},
+		{name: "value with comma", filter: Filter().Eq("spec.owner", "foo,bar")},
+		{name: "value with surrounding whitespace", filter: Filter().Eq("spec.owner", " foo")},
+		{name: "repeated field", filter: Filter().Eq("spec.type", "service").Eq("SPEC.type", "website")},
+		{name: "no values", filter: Filter().In("kind")},
+		{name: "invalid filter in set", filter: Or(Filter().Kind("api"), Filter().Eq("spec.owner", "a,b"))},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.filter.Build()
+			assert.Error(t, err, "Build should return an error")
+		})
+	}
+}
+
+// TestFilterMustBuild tests that MustBuild panics on invalid filters.
+func TestFilterMustBuild(t *testing.T) {
+	assert.Equal(t, []string{"kind=api"}, Filter().Kind("api").MustBuild(), "Filters should match")
+	assert.Equal(t, []string{"kind=api", "kind=user"}, Or(Filter().Kind("api"), Filter().Kind("user")).MustBuild(), "Filters should match")
+	assert.Panics(t, func() { Filter().Eq("foo", "bar").MustBuild() }, "MustBuild should panic on invalid filter")
+}
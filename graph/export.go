@@ -0,0 +1,371 @@
+package graph
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/datolabs-io/go-backstage/v3"
+)
+
+// DefaultExportRelations contains the relation types exported when none are specified. Only one direction of each pair of
+// relations is included, so that every relation is drawn as a single edge.
+var DefaultExportRelations = slices.Clone(DirectedRelations)
+
+// ExportOptions specifies the optional parameters of the graph exporters.
+type ExportOptions struct {
+	// Root limits the export to the entities reachable from the referenced entity, e.g. a System or a Domain.
+	Root *backstage.EntityRef
+
+	// Depth limits the number of relations followed from the root. Zero means no limit.
+	Depth int
+
+	// Relations contains the relation types to export. If empty, DefaultExportRelations are used.
+	Relations []string
+
+	// ClusterBySystem groups entities by the system they are part of.
+	ClusterBySystem bool
+}
+
+// kindStyle describes how nodes of a kind are drawn.
+type kindStyle struct {
+	dotShape  string
+	color     string
+	mermaidL  string
+	mermaidR  string
+	className string
+}
+
+// kindStyles contains styles of the built-in kinds, keyed by the lowercase kind name.
+var kindStyles = map[string]kindStyle{
+	"component": {dotShape: "box", color: "#b3e5fc", mermaidL: "[", mermaidR: "]", className: "component"},
+	"api":       {dotShape: "hexagon", color: "#c8e6c9", mermaidL: "{{", mermaidR: "}}", className: "api"},
+	"resource":  {dotShape: "cylinder", color: "#ffe0b2", mermaidL: "[(", mermaidR: ")]", className: "resource"},
+	"system":    {dotShape: "box3d", color: "#d1c4e9", mermaidL: "[[", mermaidR: "]]", className: "system"},
+	"domain":    {dotShape: "tab", color: "#f8bbd0", mermaidL: "[/", mermaidR: "/]", className: "domain"},
+	"group":     {dotShape: "ellipse", color: "#fff9c4", mermaidL: "([", mermaidR: "])", className: "group"},
+	"user":      {dotShape: "oval", color: "#f0f4c3", mermaidL: "((", mermaidR: "))", className: "user"},
+	"location":  {dotShape: "note", color: "#eeeeee", mermaidL: ">", mermaidR: "]", className: "location"},
+	"template":  {dotShape: "folder", color: "#cfd8dc", mermaidL: ">", mermaidR: "]", className: "template"},
+}
+
+// defaultKindStyle is used for nodes of kinds without a style.
+var defaultKindStyle = kindStyle{dotShape: "box", color: "#ffffff", mermaidL: "[", mermaidR: "]", className: "other"}
+
+// export is a subgraph selected for export.
+type export struct {
+	nodes    []*Node
+	ids      map[string]string
+	edges    []Edge
+	clusters map[string][]*Node
+	order    []string
+}
+
+// WriteDOT writes the graph in Graphviz DOT format.
+func (g *Graph) WriteDOT(w io.Writer, options *ExportOptions) error {
+	x, err := g.export(options)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph catalog {")
+	fmt.Fprintln(bw, `  node [style="filled"];`)
+
+	writeNode := func(indent string, n *Node) {
+		s := style(n)
+		fmt.Fprintf(bw, "%s%s [label=%s, shape=%s, fillcolor=%s];\n", indent, dotQuote(x.ids[key(n.Ref)]), dotQuote(label(n)),
+			s.dotShape, dotQuote(s.color))
+	}
+
+	for i, c := range x.order {
+		fmt.Fprintf(bw, "  subgraph %s {\n", dotQuote(fmt.Sprintf("cluster_%d", i)))
+		fmt.Fprintf(bw, "    label=%s;\n", dotQuote(clusterLabel(g, c)))
+		for _, n := range x.clusters[c] {
+			writeNode("    ", n)
+		}
+		fmt.Fprintln(bw, "  }")
+	}
+
+	for _, n := range x.clusters[""] {
+		writeNode("  ", n)
+	}
+
+	for _, e := range x.edges {
+		fmt.Fprintf(bw, "  %s -> %s [label=%s];\n", dotQuote(x.ids[key(e.From)]), dotQuote(x.ids[key(e.To)]), dotQuote(e.Type))
+	}
+
+	fmt.Fprintln(bw, "}")
+
+	return bw.Flush()
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart.
+func (g *Graph) WriteMermaid(w io.Writer, options *ExportOptions) error {
+	x, err := g.export(options)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "flowchart LR")
+
+	writeNode := func(indent string, n *Node) {
+		s := style(n)
+		fmt.Fprintf(bw, "%s%s%s\"%s\"%s\n", indent, x.ids[key(n.Ref)], s.mermaidL, mermaidEscape(label(n)), s.mermaidR)
+	}
+
+	for i, c := range x.order {
+		fmt.Fprintf(bw, "  subgraph cluster%d[\"%s\"]\n", i, mermaidEscape(clusterLabel(g, c)))
+		for _, n := range x.clusters[c] {
+			writeNode("    ", n)
+		}
+		fmt.Fprintln(bw, "  end")
+	}
+
+	for _, n := range x.clusters[""] {
+		writeNode("  ", n)
+	}
+
+	for _, e := range x.edges {
+		fmt.Fprintf(bw, "  %s -->|%s| %s\n", x.ids[key(e.From)], mermaidEscape(e.Type), x.ids[key(e.To)])
+	}
+
+	classes := map[string][]string{}
+	for _, n := range x.nodes {
+		s := style(n)
+		classes[s.className] = append(classes[s.className], x.ids[key(n.Ref)])
+	}
+
+	for _, s := range sortedStyles(classes) {
+		fmt.Fprintf(bw, "  classDef %s fill:%s\n", s.className, s.color)
+		fmt.Fprintf(bw, "  class %s %s\n", strings.Join(classes[s.className], ","), s.className)
+	}
+
+	return bw.Flush()
+}
+
+// WriteGraphML writes the graph in GraphML format. Clusters are written as nested graphs.
+func (g *Graph) WriteGraphML(w io.Writer, options *ExportOptions) error {
+	x, err := g.export(options)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, xml.Header[:len(xml.Header)-1])
+	fmt.Fprintln(bw, `<graphml xmlns="http://graphml.graphdrawing.org/xmlns">`)
+	fmt.Fprintln(bw, `  <key id="label" for="node" attr.name="label" attr.type="string"/>`)
+	fmt.Fprintln(bw, `  <key id="kind" for="node" attr.name="kind" attr.type="string"/>`)
+	fmt.Fprintln(bw, `  <key id="ref" for="node" attr.name="ref" attr.type="string"/>`)
+	fmt.Fprintln(bw, `  <key id="color" for="node" attr.name="color" attr.type="string"/>`)
+	fmt.Fprintln(bw, `  <key id="relation" for="edge" attr.name="relation" attr.type="string"/>`)
+	fmt.Fprintln(bw, `  <graph id="catalog" edgedefault="directed">`)
+
+	writeNode := func(indent string, n *Node) {
+		fmt.Fprintf(bw, "%s<node id=\"%s\">\n", indent, xmlEscape(x.ids[key(n.Ref)]))
+		fmt.Fprintf(bw, "%s  <data key=\"label\">%s</data>\n", indent, xmlEscape(label(n)))
+		fmt.Fprintf(bw, "%s  <data key=\"kind\">%s</data>\n", indent, xmlEscape(n.Ref.Kind))
+		fmt.Fprintf(bw, "%s  <data key=\"ref\">%s</data>\n", indent, xmlEscape(n.Ref.String()))
+		fmt.Fprintf(bw, "%s  <data key=\"color\">%s</data>\n", indent, style(n).color)
+		fmt.Fprintf(bw, "%s</node>\n", indent)
+	}
+
+	for i, c := range x.order {
+		id := fmt.Sprintf("cluster%d", i)
+		fmt.Fprintf(bw, "    <node id=\"%s\">\n", id)
+		fmt.Fprintf(bw, "      <data key=\"label\">%s</data>\n", xmlEscape(clusterLabel(g, c)))
+		fmt.Fprintf(bw, "      <graph id=\"%s:\" edgedefault=\"directed\">\n", id)
+		for _, n := range x.clusters[c] {
+			writeNode("        ", n)
+		}
+		fmt.Fprintln(bw, "      </graph>")
+		fmt.Fprintln(bw, "    </node>")
+	}
+
+	for _, n := range x.clusters[""] {
+		writeNode("    ", n)
+	}
+
+	for i, e := range x.edges {
+		fmt.Fprintf(bw, "    <edge id=\"e%d\" source=\"%s\" target=\"%s\">\n", i, xmlEscape(x.ids[key(e.From)]), xmlEscape(x.ids[key(e.To)]))
+		fmt.Fprintf(bw, "      <data key=\"relation\">%s</data>\n", xmlEscape(e.Type))
+		fmt.Fprintln(bw, "    </edge>")
+	}
+
+	fmt.Fprintln(bw, "  </graph>")
+	fmt.Fprintln(bw, "</graphml>")
+
+	return bw.Flush()
+}
+
+// export selects the nodes and edges to export.
+func (g *Graph) export(options *ExportOptions) (*export, error) {
+	opts := ExportOptions{}
+	if options != nil {
+		opts = *options
+	}
+
+	if len(opts.Relations) == 0 {
+		opts.Relations = DefaultExportRelations
+	}
+
+	selected := map[string]bool{}
+	if opts.Root == nil {
+		for k := range g.nodes {
+			selected[k] = true
+		}
+	} else {
+		root := key(*opts.Root)
+		if _, ok := g.nodes[root]; !ok {
+			return nil, fmt.Errorf("root entity not found: %s", opts.Root)
+		}
+
+		selected[root] = true
+		frontier := []string{root}
+		for depth := 0; len(frontier) > 0 && (opts.Depth == 0 || depth < opts.Depth); depth++ {
+			var next []string
+			for _, k := range frontier {
+				for _, e := range filterEdges(g.out[k], opts.Relations) {
+					if to := key(e.To); !selected[to] {
+						selected[to] = true
+						next = append(next, to)
+					}
+				}
+
+				// Parts of a system or a domain point to it, so they are followed against the direction of the relation.
+				for _, e := range filterEdges(g.in[k], []string{backstage.RelationPartOf}) {
+					if from := key(e.From); slices.Contains(opts.Relations, e.Type) && !selected[from] {
+						selected[from] = true
+						next = append(next, from)
+					}
+				}
+			}
+			frontier = next
+		}
+	}
+
+	x := &export{
+		ids:      map[string]string{},
+		clusters: map[string][]*Node{},
+	}
+
+	for _, k := range g.sortedKeys() {
+		if !selected[k] {
+			continue
+		}
+
+		n := g.nodes[k]
+		x.ids[k] = fmt.Sprintf("n%d", len(x.nodes))
+		x.nodes = append(x.nodes, n)
+
+		cluster := ""
+		if opts.ClusterBySystem {
+			cluster = g.system(n)
+		}
+
+		if _, ok := x.clusters[cluster]; !ok && cluster != "" {
+			x.order = append(x.order, cluster)
+		}
+		x.clusters[cluster] = append(x.clusters[cluster], n)
+	}
+	slices.Sort(x.order)
+
+	for _, k := range g.sortedKeys() {
+		if !selected[k] {
+			continue
+		}
+
+		for _, e := range filterEdges(g.out[k], opts.Relations) {
+			if selected[key(e.To)] {
+				x.edges = append(x.edges, e)
+			}
+		}
+	}
+
+	return x, nil
+}
+
+// system returns the key of the system the node belongs to, or an empty string if it does not belong to any system.
+func (g *Graph) system(n *Node) string {
+	k := key(n.Ref)
+	if strings.EqualFold(n.Ref.Kind, backstage.KindSystem) {
+		return k
+	}
+
+	for _, e := range filterEdges(g.out[k], []string{backstage.RelationPartOf}) {
+		if strings.EqualFold(e.To.Kind, backstage.KindSystem) {
+			return key(e.To)
+		}
+	}
+
+	return ""
+}
+
+// clusterLabel returns the label of the cluster of the system with the given key.
+func clusterLabel(g *Graph, k string) string {
+	return label(g.nodes[k])
+}
+
+// label returns the label of the node: title of the entity, if it has one, and its name otherwise.
+func label(n *Node) string {
+	if n.Entity != nil && n.Entity.Metadata.Title != "" {
+		return n.Entity.Metadata.Title
+	}
+
+	if !strings.EqualFold(n.Ref.Namespace, backstage.DefaultNamespaceName) {
+		return n.Ref.Namespace + "/" + n.Ref.Name
+	}
+
+	return n.Ref.Name
+}
+
+// style returns the style of the node, chosen by its kind.
+func style(n *Node) kindStyle {
+	if s, ok := kindStyles[strings.ToLower(n.Ref.Kind)]; ok {
+		return s
+	}
+
+	return defaultKindStyle
+}
+
+// sortedStyles returns styles of the used classes, ordered by class name.
+func sortedStyles(classes map[string][]string) []kindStyle {
+	var styles []kindStyle
+	for _, s := range kindStyles {
+		if _, ok := classes[s.className]; ok {
+			styles = append(styles, s)
+		}
+	}
+
+	if _, ok := classes[defaultKindStyle.className]; ok {
+		styles = append(styles, defaultKindStyle)
+	}
+
+	slices.SortFunc(styles, func(a, b kindStyle) int {
+		return strings.Compare(a.className, b.className)
+	})
+
+	return styles
+}
+
+// dotQuote returns the string as a quoted DOT identifier.
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// mermaidEscape escapes characters that cannot be used in Mermaid labels.
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "|", "#124;", "\n", " ").Replace(s)
+}
+
+// xmlEscape escapes the string for use in XML text and attributes.
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+
+	return b.String()
+}
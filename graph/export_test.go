@@ -0,0 +1,147 @@
+package graph
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/datolabs-io/go-backstage/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+// platform returns a graph of a domain with a system of two components, and a component outside of the system.
+func platform() *Graph {
+	g := New([]backstage.Entity{
+		entity("Domain", "commerce", ""),
+		entity("System", "shop", backstage.RelationPartOf, "domain:default/commerce"),
+		entity("Component", "web", backstage.RelationPartOf, "system:default/shop"),
+		entity("Component", "checkout", backstage.RelationPartOf, "system:default/shop"),
+		entity("Component", "billing", backstage.RelationOwnedBy, "group:default/finance"),
+	})
+
+	g.AddEdge(Edge{From: ref("component:default/web"), To: ref("component:default/checkout"), Type: backstage.RelationDependsOn})
+	g.AddEdge(Edge{From: ref("component:default/checkout"), To: ref("component:default/billing"), Type: backstage.RelationDependsOn})
+	g.AddEdge(Edge{From: ref("component:default/billing"), To: ref("component:default/checkout"), Type: backstage.RelationDependencyOf})
+
+	return g
+}
+
+// TestGraphWriteDOT tests exporting of the graph in Graphviz DOT format.
+func TestGraphWriteDOT(t *testing.T) {
+	var b strings.Builder
+	err := platform().WriteDOT(&b, &ExportOptions{ClusterBySystem: true})
+	out := b.String()
+
+	assert.NoError(t, err, "WriteDOT should not return an error")
+	assert.True(t, strings.HasPrefix(out, "digraph catalog {"), "Output should be a directed graph")
+	assert.Contains(t, out, `subgraph "cluster_0" {`, "Output should contain a cluster of the system")
+	assert.Contains(t, out, `label="shop";`, "Cluster should be labeled by the system")
+	assert.Contains(t, out, `[label="web", shape=box, fillcolor="#b3e5fc"];`, "Components should be styled by kind")
+	assert.Contains(t, out, `shape=tab`, "Domain should be styled by kind")
+	assert.Contains(t, out, `[label="dependsOn"];`, "Edges should be labeled by relation type")
+	assert.NotContains(t, out, backstage.RelationDependencyOf, "Inverse relations should not be exported by default")
+}
+
+// TestGraphWriteMermaid tests exporting of the graph as a Mermaid flowchart.
+func TestGraphWriteMermaid(t *testing.T) {
+	root := ref("system:default/shop")
+
+	var b strings.Builder
+	err := platform().WriteMermaid(&b, &ExportOptions{Root: &root, Relations: []string{backstage.RelationPartOf}})
+
+	expected := `flowchart LR
+  n0["checkout"]
+  n1["web"]
+  n2[/"commerce"/]
+  n3[["shop"]]
+  n0 -->|partOf| n3
+  n1 -->|partOf| n3
+  n3 -->|partOf| n2
+  classDef component fill:#b3e5fc
+  class n0,n1 component
+  classDef domain fill:#f8bbd0
+  class n2 domain
+  classDef system fill:#d1c4e9
+  class n3 system
+`
+
+	assert.NoError(t, err, "WriteMermaid should not return an error")
+	assert.Equal(t, expected, b.String(), "Flowchart should contain parts of the system")
+}
+
+// TestGraphExportRoot tests limiting of the export to the entities reachable from the root.
+func TestGraphExportRoot(t *testing.T) {
+	tests := []struct {
+		name     string
+		root     string
+		depth    int
+		expected []string
+	}{
+		{
+			name:     "domain",
+			root:     "domain:default/commerce",
+			expected: []string{"billing", "checkout", "commerce", "finance", "shop", "web"},
+		},
+		{
+			name:     "domain with depth",
+			root:     "domain:default/commerce",
+			depth:    1,
+			expected: []string{"commerce", "shop"},
+		},
+		{
+			name:     "system with depth",
+			root:     "system:default/shop",
+			depth:    2,
+			expected: []string{"billing", "checkout", "commerce", "shop", "web"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := ref(test.root)
+			x, err := platform().export(&ExportOptions{Root: &root, Depth: test.depth})
+
+			var names []string
+			for _, n := range x.nodes {
+				names = append(names, n.Ref.Name)
+			}
+
+			assert.NoError(t, err, "export should not return an error")
+			assert.ElementsMatch(t, test.expected, names, "Export should contain the reachable entities")
+		})
+	}
+
+	missing := ref("system:default/missing")
+	err := platform().WriteDOT(&strings.Builder{}, &ExportOptions{Root: &missing})
+	assert.EqualError(t, err, "root entity not found: system:default/missing", "Missing root should return an error")
+}
+
+// TestGraphWriteGraphML tests exporting of the graph in GraphML format.
+func TestGraphWriteGraphML(t *testing.T) {
+	var b strings.Builder
+	err := platform().WriteGraphML(&b, &ExportOptions{ClusterBySystem: true})
+
+	var doc struct {
+		Graph struct {
+			Nodes []struct {
+				ID    string `xml:"id,attr"`
+				Graph *struct {
+					Nodes []struct {
+						ID string `xml:"id,attr"`
+					} `xml:"node"`
+				} `xml:"graph"`
+			} `xml:"node"`
+			Edges []struct {
+				Data string `xml:"data"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	xmlErr := xml.Unmarshal([]byte(b.String()), &doc)
+
+	assert.NoError(t, err, "WriteGraphML should not return an error")
+	assert.NoError(t, xmlErr, "Output should be valid XML")
+	assert.Equal(t, "cluster0", doc.Graph.Nodes[0].ID, "Cluster should be written first")
+	assert.Len(t, doc.Graph.Nodes[0].Graph.Nodes, 3, "Cluster should contain the system and its parts")
+	assert.Len(t, doc.Graph.Nodes, 4, "Entities outside of clusters should be top-level nodes")
+	assert.Len(t, doc.Graph.Edges, 6, "Edges of default relations should be exported")
+}
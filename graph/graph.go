@@ -0,0 +1,428 @@
+/*
+Package graph provides an in-memory graph of Backstage catalog entities, connected by their relations.
+
+The graph is built from a set of entities, e.g. returned by the Entities.List method of the Backstage client:
+
+	entities, _, err := client.Catalog.Entities.List(ctx, nil)
+	g := graph.New(entities)
+
+It can then be used to answer questions like what depends on a component, or in which order components should be deployed:
+
+	dependents := g.Reachable(ref, backstage.RelationDependencyOf)
+	order, err := g.TopologicalOrder(backstage.RelationDependsOn)
+
+The graph, or a part of it rooted at e.g. a System or a Domain, can be exported for visualization as Graphviz DOT, Mermaid
+flowchart or GraphML:
+
+	err := g.WriteMermaid(os.Stdout, &graph.ExportOptions{Root: &ref, Depth: 2, ClusterBySystem: true})
+*/
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/datolabs-io/go-backstage/v3"
+)
+
+// ErrCycle is returned when the graph contains a cycle where an acyclic graph is expected.
+var ErrCycle = errors.New("graph contains a cycle")
+
+// DirectedRelations contains one direction of each pair of relations between the built-in kinds, e.g. "ownedBy" but not its
+// inverse "ownerOf". The catalog stores both directions of every relation, so only these are followed by Cycles and
+// TopologicalOrder when no relation types are specified.
+var DirectedRelations = []string{
+	backstage.RelationOwnedBy,
+	backstage.RelationDependsOn,
+	backstage.RelationProvidesApi,
+	backstage.RelationConsumesApi,
+	backstage.RelationPartOf,
+	backstage.RelationChildOf,
+	backstage.RelationMemberOf,
+}
+
+// Graph is a directed multigraph of entities. Its edges are the relations of the entities, so two entities can be connected by
+// several edges of different relation types.
+type Graph struct {
+	nodes map[string]*Node
+	out   map[string][]Edge
+	in    map[string][]Edge
+}
+
+// Node is a single entity in the graph.
+type Node struct {
+	// Ref is the reference to the entity.
+	Ref backstage.EntityRef
+
+	// Entity is the entity itself. It is nil if the entity is only known as a target of a relation.
+	Entity *backstage.Entity
+}
+
+// Edge is a directed relation between two entities.
+type Edge struct {
+	// From is the reference to the source entity of the relation.
+	From backstage.EntityRef
+
+	// To is the reference to the target entity of the relation.
+	To backstage.EntityRef
+
+	// Type is the type of the relation, e.g. "dependsOn".
+	Type string
+}
+
+// New returns a graph of the entities, connected by their relations. Entities referenced by relations, but missing from the
+// set, are added to the graph as nodes without entity.
+func New(entities []backstage.Entity) *Graph {
+	g := &Graph{
+		nodes: map[string]*Node{},
+		out:   map[string][]Edge{},
+		in:    map[string][]Edge{},
+	}
+
+	for i := range entities {
+		e := &entities[i]
+		g.addNode(e.Ref()).Entity = e
+	}
+
+	for i := range entities {
+		e := &entities[i]
+		for _, r := range e.Relations {
+			to, err := r.Ref()
+			if err != nil {
+				continue
+			}
+
+			g.AddEdge(Edge{From: e.Ref(), To: to, Type: r.Type})
+		}
+	}
+
+	return g
+}
+
+// AddEdge adds an edge to the graph, adding its nodes if they are missing. Adding the same edge again has no effect.
+func (g *Graph) AddEdge(edge Edge) {
+	from, to := key(edge.From), key(edge.To)
+	g.addNode(edge.From)
+	g.addNode(edge.To)
+
+	for _, e := range g.out[from] {
+		if key(e.To) == to && e.Type == edge.Type {
+			return
+		}
+	}
+
+	g.out[from] = append(g.out[from], edge)
+	g.in[to] = append(g.in[to], edge)
+}
+
+// Node returns the node of the referenced entity.
+func (g *Graph) Node(ref backstage.EntityRef) (*Node, bool) {
+	n, ok := g.nodes[key(ref)]
+	return n, ok
+}
+
+// Nodes returns all nodes of the graph, ordered by their references.
+func (g *Graph) Nodes() []*Node {
+	nodes := make([]*Node, 0, len(g.nodes))
+	for _, k := range g.sortedKeys() {
+		nodes = append(nodes, g.nodes[k])
+	}
+
+	return nodes
+}
+
+// OutEdges returns the edges starting at the referenced entity, limited to the given relation types (all, if not specified).
+func (g *Graph) OutEdges(ref backstage.EntityRef, types ...string) []Edge {
+	return filterEdges(g.out[key(ref)], types)
+}
+
+// InEdges returns the edges ending at the referenced entity, limited to the given relation types (all, if not specified).
+func (g *Graph) InEdges(ref backstage.EntityRef, types ...string) []Edge {
+	return filterEdges(g.in[key(ref)], types)
+}
+
+// Neighbors returns references to the entities directly related to the referenced entity by outgoing relations of the given
+// types (all, if not specified).
+func (g *Graph) Neighbors(ref backstage.EntityRef, types ...string) []backstage.EntityRef {
+	var neighbors []backstage.EntityRef
+	seen := map[string]bool{}
+	for _, e := range g.OutEdges(ref, types...) {
+		if k := key(e.To); !seen[k] {
+			seen[k] = true
+			neighbors = append(neighbors, e.To)
+		}
+	}
+
+	return neighbors
+}
+
+// Reachable returns references to all entities transitively reachable from the referenced entity by outgoing relations of the
+// given types (all, if not specified), in breadth-first order. The entity itself is not included, unless it is part of a cycle.
+func (g *Graph) Reachable(ref backstage.EntityRef, types ...string) []backstage.EntityRef {
+	var reachable []backstage.EntityRef
+	seen := map[string]bool{}
+	queue := []backstage.EntityRef{ref}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, e := range g.OutEdges(current, types...) {
+			if k := key(e.To); !seen[k] {
+				seen[k] = true
+				reachable = append(reachable, e.To)
+				queue = append(queue, e.To)
+			}
+		}
+	}
+
+	return reachable
+}
+
+// ShortestPath returns the edges of a shortest path between the referenced entities, following outgoing relations of the
+// given types (all, if not specified). It returns false if there is no such path.
+func (g *Graph) ShortestPath(from backstage.EntityRef, to backstage.EntityRef, types ...string) ([]Edge, bool) {
+	target := key(to)
+	if key(from) == target {
+		return nil, true
+	}
+
+	via := map[string]Edge{}
+	seen := map[string]bool{key(from): true}
+	queue := []backstage.EntityRef{from}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, e := range g.OutEdges(current, types...) {
+			k := key(e.To)
+			if seen[k] {
+				continue
+			}
+
+			seen[k] = true
+			via[k] = e
+
+			if k == target {
+				return g.unwind(via, from, to), true
+			}
+
+			queue = append(queue, e.To)
+		}
+	}
+
+	return nil, false
+}
+
+// Cycles returns the cycles formed by relations of the given types (DirectedRelations, if not specified). Each cycle is
+// returned as a set of references to the entities forming a strongly connected component, ordered by their references.
+func (g *Graph) Cycles(types ...string) [][]backstage.EntityRef {
+	if len(types) == 0 {
+		types = DirectedRelations
+	}
+
+	index := 0
+	indices := map[string]int{}
+	lowlinks := map[string]int{}
+	onStack := map[string]bool{}
+	var stack []string
+	var cycles [][]backstage.EntityRef
+
+	var connect func(k string)
+	connect = func(k string) {
+		indices[k], lowlinks[k] = index, index
+		index++
+		stack = append(stack, k)
+		onStack[k] = true
+
+		selfLoop := false
+		for _, e := range filterEdges(g.out[k], types) {
+			next := key(e.To)
+			switch {
+			case next == k:
+				selfLoop = true
+			case !containsKey(indices, next):
+				connect(next)
+				lowlinks[k] = min(lowlinks[k], lowlinks[next])
+			case onStack[next]:
+				lowlinks[k] = min(lowlinks[k], indices[next])
+			}
+		}
+
+		if lowlinks[k] != indices[k] {
+			return
+		}
+
+		var component []string
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+			if last == k {
+				break
+			}
+		}
+
+		if len(component) > 1 || selfLoop {
+			slices.Sort(component)
+			cycle := make([]backstage.EntityRef, 0, len(component))
+			for _, c := range component {
+				cycle = append(cycle, g.nodes[c].Ref)
+			}
+			cycles = append(cycles, cycle)
+		}
+	}
+
+	for _, k := range g.sortedKeys() {
+		if !containsKey(indices, k) {
+			connect(k)
+		}
+	}
+
+	slices.SortFunc(cycles, func(a, b []backstage.EntityRef) int {
+		return strings.Compare(key(a[0]), key(b[0]))
+	})
+
+	return cycles
+}
+
+// TopologicalOrder returns references to all entities, ordered so that every entity comes after the targets of its outgoing
+// relations of the given types (DirectedRelations, if not specified). For the "dependsOn" relation, that is the order in
+// which entities can be deployed. Entities that are not ordered relative to each other are ordered by their references. It
+// returns an error wrapping ErrCycle if the relations form a cycle, including an entity related to itself, as reported by
+// Cycles.
+func (g *Graph) TopologicalOrder(types ...string) ([]backstage.EntityRef, error) {
+	if len(types) == 0 {
+		types = DirectedRelations
+	}
+
+	pending := map[string]int{}
+	for k := range g.nodes {
+		pending[k] = len(uniqueTargets(filterEdges(g.out[k], types)))
+	}
+
+	var ready []string
+	for k, n := range pending {
+		if n == 0 {
+			ready = append(ready, k)
+		}
+	}
+	slices.Sort(ready)
+
+	order := make([]backstage.EntityRef, 0, len(g.nodes))
+	for len(ready) > 0 {
+		k := ready[0]
+		ready = ready[1:]
+		order = append(order, g.nodes[k].Ref)
+
+		var unblocked []string
+		for source := range uniqueSources(filterEdges(g.in[k], types)) {
+			pending[source]--
+			if pending[source] == 0 {
+				unblocked = append(unblocked, source)
+			}
+		}
+
+		ready = append(ready, unblocked...)
+		slices.Sort(ready)
+	}
+
+	if len(order) < len(g.nodes) {
+		return nil, fmt.Errorf("%w: %d entities cannot be ordered", ErrCycle, len(g.nodes)-len(order))
+	}
+
+	return order, nil
+}
+
+// addNode adds a node for the referenced entity, if it is missing, and returns it.
+func (g *Graph) addNode(ref backstage.EntityRef) *Node {
+	k := key(ref)
+	if n, ok := g.nodes[k]; ok {
+		return n
+	}
+
+	if ref.Namespace == "" {
+		ref.Namespace = backstage.DefaultNamespaceName
+	}
+
+	n := &Node{Ref: ref}
+	g.nodes[k] = n
+
+	return n
+}
+
+// unwind returns the edges of the path from one entity to the other, as recorded during breadth-first search.
+func (g *Graph) unwind(via map[string]Edge, from backstage.EntityRef, to backstage.EntityRef) []Edge {
+	var path []Edge
+	for k := key(to); k != key(from); {
+		e := via[k]
+		path = append(path, e)
+		k = key(e.From)
+	}
+
+	slices.Reverse(path)
+
+	return path
+}
+
+// sortedKeys returns keys of all nodes in sorted order.
+func (g *Graph) sortedKeys() []string {
+	keys := make([]string, 0, len(g.nodes))
+	for k := range g.nodes {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+// key returns the key of the referenced entity in the graph. References are compared case-insensitively.
+func key(ref backstage.EntityRef) string {
+	return strings.ToLower(ref.String())
+}
+
+// filterEdges returns the edges of the given relation types, or all edges if no types are specified.
+func filterEdges(edges []Edge, types []string) []Edge {
+	if len(types) == 0 {
+		return edges
+	}
+
+	var filtered []Edge
+	for _, e := range edges {
+		if slices.Contains(types, e.Type) {
+			filtered = append(filtered, e)
+		}
+	}
+
+	return filtered
+}
+
+// uniqueTargets returns keys of the distinct targets of the edges.
+func uniqueTargets(edges []Edge) map[string]bool {
+	targets := map[string]bool{}
+	for _, e := range edges {
+		targets[key(e.To)] = true
+	}
+
+	return targets
+}
+
+// uniqueSources returns keys of the distinct sources of the edges.
+func uniqueSources(edges []Edge) map[string]bool {
+	sources := map[string]bool{}
+	for _, e := range edges {
+		sources[key(e.From)] = true
+	}
+
+	return sources
+}
+
+// containsKey returns true if the map contains the key.
+func containsKey(m map[string]int, k string) bool {
+	_, ok := m[k]
+	return ok
+}
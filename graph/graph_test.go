@@ -0,0 +1,193 @@
+package graph
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/datolabs-io/go-backstage/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+// entity returns an entity of the kind and name, with relations of the given type to the targets.
+func entity(kind string, name string, relation string, targets ...string) backstage.Entity {
+	e := backstage.Entity{
+		Kind:     kind,
+		Metadata: backstage.EntityMeta{Name: name, Namespace: "default"},
+	}
+
+	for _, t := range targets {
+		e.Relations = append(e.Relations, backstage.EntityRelation{Type: relation, TargetRef: t})
+	}
+
+	return e
+}
+
+// ref returns the parsed reference.
+func ref(s string) backstage.EntityRef {
+	r, _ := backstage.ParseEntityRef(s, backstage.EntityRefDefaults{})
+	return r
+}
+
+// refs returns canonical strings of the references.
+func refs(r []backstage.EntityRef) []string {
+	var s []string
+	for _, ref := range r {
+		s = append(s, ref.String())
+	}
+
+	return s
+}
+
+// services returns a graph of components depending on each other: web -> api -> db, web -> cache.
+func services() *Graph {
+	return New([]backstage.Entity{
+		entity("Component", "web", backstage.RelationDependsOn, "component:default/api", "resource:default/cache"),
+		entity("Component", "api", backstage.RelationDependsOn, "resource:default/db"),
+		entity("Resource", "db", backstage.RelationOwnedBy, "group:default/dba"),
+		entity("Resource", "cache", backstage.RelationDependsOn),
+	})
+}
+
+// TestNew tests building of a graph from entities.
+func TestNew(t *testing.T) {
+	const dataFile = "../testdata/entities.json"
+
+	var entities []backstage.Entity
+	data, _ := os.ReadFile(dataFile)
+	err := json.Unmarshal(data, &entities)
+
+	assert.FileExists(t, dataFile, "Test data file should exist")
+	assert.NoError(t, err, "Unmarshal should not return an error")
+
+	g := New(entities)
+
+	n, ok := g.Node(ref("Component:default/example-website"))
+	assert.True(t, ok, "Entity should be a node of the graph")
+	assert.Equal(t, "example-website", n.Entity.Metadata.Name, "Node should contain the entity")
+	assert.Len(t, g.Nodes(), len(entities), "All entities should be nodes of the graph")
+
+	owners := g.Neighbors(ref("component:default/example-website"), backstage.RelationOwnedBy)
+	assert.Equal(t, []string{"group:default/guests"}, refs(owners), "Owner should be a neighbor")
+
+	owned := g.InEdges(ref("group:default/guests"), backstage.RelationOwnedBy)
+	assert.Len(t, owned, 3, "Owned entities should be connected by incoming edges")
+}
+
+// TestGraphMissingTarget tests that targets of relations missing from the entities are added as nodes without entity.
+func TestGraphMissingTarget(t *testing.T) {
+	g := services()
+
+	n, ok := g.Node(ref("group:default/dba"))
+	assert.True(t, ok, "Target should be a node of the graph")
+	assert.Nil(t, n.Entity, "Target should have no entity")
+}
+
+// TestGraphAddEdge tests that duplicate edges are ignored.
+func TestGraphAddEdge(t *testing.T) {
+	g := services()
+	g.AddEdge(Edge{From: ref("component:default/WEB"), To: ref("component:default/api"), Type: backstage.RelationDependsOn})
+
+	assert.Len(t, g.OutEdges(ref("component:default/web")), 2, "Duplicate edge should be ignored")
+}
+
+// TestGraphReachable tests transitive closure along relations.
+func TestGraphReachable(t *testing.T) {
+	g := services()
+
+	actual := g.Reachable(ref("component:default/web"), backstage.RelationDependsOn)
+	assert.Equal(t, []string{"component:default/api", "resource:default/cache", "resource:default/db"}, refs(actual))
+
+	actual = g.Reachable(ref("component:default/web"))
+	assert.Contains(t, refs(actual), "group:default/dba", "All relation types should be followed if none are specified")
+}
+
+// TestGraphShortestPath tests finding of the shortest path between entities.
+func TestGraphShortestPath(t *testing.T) {
+	g := services()
+
+	path, ok := g.ShortestPath(ref("component:default/web"), ref("group:default/dba"))
+	assert.True(t, ok, "Path should be found")
+	assert.Len(t, path, 3, "Path should contain all edges")
+	assert.Equal(t, "resource:default/db", path[2].From.String(), "Path should end with the last edge")
+
+	_, ok = g.ShortestPath(ref("component:default/web"), ref("group:default/dba"), backstage.RelationDependsOn)
+	assert.False(t, ok, "Path should not be found along other relation types")
+
+	path, ok = g.ShortestPath(ref("component:default/web"), ref("component:default/web"))
+	assert.True(t, ok, "Path to itself should be found")
+	assert.Empty(t, path, "Path to itself should be empty")
+}
+
+// TestGraphCycles tests detection of cycles.
+func TestGraphCycles(t *testing.T) {
+	assert.Empty(t, services().Cycles(backstage.RelationDependsOn), "Acyclic graph should have no cycles")
+
+	g := New([]backstage.Entity{
+		entity("Component", "a", backstage.RelationDependsOn, "component:default/b"),
+		entity("Component", "b", backstage.RelationDependsOn, "component:default/c"),
+		entity("Component", "c", backstage.RelationDependsOn, "component:default/a"),
+		entity("Component", "d", backstage.RelationDependsOn, "component:default/d"),
+	})
+
+	actual := g.Cycles(backstage.RelationDependsOn)
+	assert.Len(t, actual, 2, "Both cycles should be found")
+	assert.Equal(t, []string{"component:default/a", "component:default/b", "component:default/c"}, refs(actual[0]))
+	assert.Equal(t, []string{"component:default/d"}, refs(actual[1]), "Self-loop should be a cycle")
+}
+
+// TestGraphCycles_DefaultRelations tests that inverse pairs of relations, as stored by the catalog, do not form cycles when
+// no relation types are specified.
+func TestGraphCycles_DefaultRelations(t *testing.T) {
+	web := entity("Component", "web", backstage.RelationDependsOn, "component:default/api")
+	web.Relations = append(web.Relations,
+		backstage.EntityRelation{Type: backstage.RelationOwnedBy, TargetRef: "group:default/team-a"},
+		backstage.EntityRelation{Type: backstage.RelationPartOf, TargetRef: "system:default/shop"},
+	)
+
+	api := entity("Component", "api", backstage.RelationDependencyOf, "component:default/web")
+	api.Relations = append(api.Relations, backstage.EntityRelation{Type: backstage.RelationOwnedBy, TargetRef: "group:default/team-a"})
+
+	g := New([]backstage.Entity{
+		web,
+		api,
+		entity("Group", "team-a", backstage.RelationOwnerOf, "component:default/web", "component:default/api"),
+		entity("System", "shop", backstage.RelationHasPart, "component:default/web"),
+	})
+
+	assert.NotEmpty(t, g.Cycles(backstage.RelationOwnedBy, backstage.RelationOwnerOf), "Inverse relations should form cycles")
+	assert.Empty(t, g.Cycles(), "Inverse relations should not be followed by default")
+
+	actual, err := g.TopologicalOrder()
+	assert.NoError(t, err, "Catalog relations should be ordered by default")
+	assert.Equal(t, []string{
+		"group:default/team-a",
+		"component:default/api",
+		"system:default/shop",
+		"component:default/web",
+	}, refs(actual), "Targets of the relations should come first")
+}
+
+// TestGraphTopologicalOrder tests ordering of entities by their dependencies.
+func TestGraphTopologicalOrder(t *testing.T) {
+	actual, err := services().TopologicalOrder(backstage.RelationDependsOn)
+	assert.NoError(t, err, "Acyclic graph should be ordered")
+	assert.Equal(t, []string{
+		"group:default/dba",
+		"resource:default/cache",
+		"resource:default/db",
+		"component:default/api",
+		"component:default/web",
+	}, refs(actual), "Dependencies should come first")
+
+	g := New([]backstage.Entity{
+		entity("Component", "a", backstage.RelationDependsOn, "component:default/b"),
+		entity("Component", "b", backstage.RelationDependsOn, "component:default/a"),
+	})
+	_, err = g.TopologicalOrder(backstage.RelationDependsOn)
+	assert.ErrorIs(t, err, ErrCycle, "Cyclic graph should not be ordered")
+
+	g = New([]backstage.Entity{entity("Component", "a", backstage.RelationDependsOn, "component:default/a")})
+	_, err = g.TopologicalOrder(backstage.RelationDependsOn)
+	assert.ErrorIs(t, err, ErrCycle, "Self-loop should not be ordered")
+}
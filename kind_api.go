@@ -2,6 +2,7 @@ package backstage
 
 import (
 	"context"
+	"iter"
 	"net/http"
 )
 
@@ -42,8 +43,20 @@ type ApiEntityV1alpha1Spec struct {
 	System string `json:"system,omitempty" yaml:"system,omitempty"`
 }
 
+// OwnerRef returns the parsed reference to the owner, with kind defaulting to "Group" and namespace to ns ("default", if not
+// specified). It returns nil if the reference is not set.
+func (s *ApiEntityV1alpha1Spec) OwnerRef(ns string) (*EntityRef, error) {
+	return parseOptionalRef(s.Owner, KindGroup, ns)
+}
+
+// SystemRef returns the parsed reference to the system, with kind defaulting to "System" and namespace to ns ("default", if not
+// specified). It returns nil if the reference is not set.
+func (s *ApiEntityV1alpha1Spec) SystemRef(ns string) (*EntityRef, error) {
+	return parseOptionalRef(s.System, KindSystem, ns)
+}
+
 // apiService handles communication with the API related methods of the Backstage Catalog API.
-type apiService typedEntityService[ComponentEntityV1alpha1]
+type apiService typedEntityService[ApiEntityV1alpha1]
 
 // newApiService returns a new instance of API-type entityService.
 func newApiService(s *entityService) *apiService {
@@ -58,3 +71,21 @@ func (s *apiService) Get(ctx context.Context, n string, ns string) (*ApiEntityV1
 	cs := (typedEntityService[ApiEntityV1alpha1])(*s)
 	return cs.get(ctx, KindAPI, n, ns)
 }
+
+// List returns a list of API entities. It can optionally be filtered by a set of conditions and limited to a set of fields.
+func (s *apiService) List(ctx context.Context, options *ListEntityOptions) ([]ApiEntityV1alpha1, *http.Response, error) {
+	cs := (typedEntityService[ApiEntityV1alpha1])(*s)
+	return cs.list(ctx, KindAPI, options)
+}
+
+// ListAll pages through all API entities matching the options, calling fn for each page of at most pageSize entities.
+func (s *apiService) ListAll(ctx context.Context, options *ListEntityOptions, pageSize int, fn func(*EntityPage[ApiEntityV1alpha1]) error) error {
+	cs := (typedEntityService[ApiEntityV1alpha1])(*s)
+	return cs.listAll(ctx, KindAPI, options, pageSize, fn)
+}
+
+// Stream returns an iterator over all API entities matching the options, fetched in pages of at most pageSize entities.
+func (s *apiService) Stream(ctx context.Context, options *ListEntityOptions, pageSize int) iter.Seq2[ApiEntityV1alpha1, error] {
+	cs := (typedEntityService[ApiEntityV1alpha1])(*s)
+	return cs.stream(ctx, KindAPI, options, pageSize)
+}
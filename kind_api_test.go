@@ -43,3 +43,37 @@ func TestKindApiGet(t *testing.T) {
 	assert.NotEmpty(t, resp, "Response should not be empty")
 	assert.EqualValues(t, &expected, actual, "Response body should match the one from the server")
 }
+
+// TestKindApiList tests functionality of listing APIs.
+func TestKindApiList(t *testing.T) {
+	const dataFile = "testdata/api.json"
+
+	expected := ApiEntityV1alpha1{}
+	expectedData, _ := os.ReadFile(dataFile)
+	err := json.Unmarshal(expectedData, &expected)
+
+	assert.FileExists(t, dataFile, "Test data file should exist")
+	assert.NoError(t, err, "Unmarshal should not return an error")
+
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("filter", "kind=api,metadata.namespace=default").
+		Reply(200).
+		JSON([]json.RawMessage{expectedData})
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	s := newApiService(&entityService{
+		client:  c,
+		apiPath: "/catalog/entities",
+	})
+
+	actual, resp, err := s.List(context.Background(), &ListEntityOptions{
+		Filters: []string{"metadata.namespace=default"},
+	})
+	assert.NoError(t, err, "List should not return an error")
+	assert.NotEmpty(t, resp, "Response should not be empty")
+	assert.EqualValues(t, []ApiEntityV1alpha1{expected}, actual, "Response body should match the one from the server")
+}
@@ -2,6 +2,7 @@ package backstage
 
 import (
 	"context"
+	"iter"
 	"net/http"
 )
 
@@ -51,6 +52,42 @@ type ComponentEntityV1alpha1Spec struct {
 	System string `json:"system,omitempty" yaml:"system,omitempty"`
 }
 
+// OwnerRef returns the parsed reference to the owner, with kind defaulting to "Group" and namespace to ns ("default", if not
+// specified). It returns nil if the reference is not set.
+func (s *ComponentEntityV1alpha1Spec) OwnerRef(ns string) (*EntityRef, error) {
+	return parseOptionalRef(s.Owner, KindGroup, ns)
+}
+
+// SubcomponentOfRef returns the parsed reference to the parent component, with kind defaulting to "Component" and namespace to ns
+// ("default", if not specified). It returns nil if the reference is not set.
+func (s *ComponentEntityV1alpha1Spec) SubcomponentOfRef(ns string) (*EntityRef, error) {
+	return parseOptionalRef(s.SubcomponentOf, KindComponent, ns)
+}
+
+// ProvidesApisRefs returns the parsed references to the provided APIs, with kind defaulting to "API" and namespace to ns
+// ("default", if not specified).
+func (s *ComponentEntityV1alpha1Spec) ProvidesApisRefs(ns string) ([]EntityRef, error) {
+	return parseRefs(s.ProvidesApis, KindAPI, ns)
+}
+
+// ConsumesApisRefs returns the parsed references to the consumed APIs, with kind defaulting to "API" and namespace to ns
+// ("default", if not specified).
+func (s *ComponentEntityV1alpha1Spec) ConsumesApisRefs(ns string) ([]EntityRef, error) {
+	return parseRefs(s.ConsumesApis, KindAPI, ns)
+}
+
+// DependsOnRefs returns the parsed references to the dependencies, with namespace defaulting to ns ("default", if not specified);
+// the kind is required.
+func (s *ComponentEntityV1alpha1Spec) DependsOnRefs(ns string) ([]EntityRef, error) {
+	return parseRefs(s.DependsOn, "", ns)
+}
+
+// SystemRef returns the parsed reference to the system, with kind defaulting to "System" and namespace to ns ("default", if not
+// specified). It returns nil if the reference is not set.
+func (s *ComponentEntityV1alpha1Spec) SystemRef(ns string) (*EntityRef, error) {
+	return parseOptionalRef(s.System, KindSystem, ns)
+}
+
 // componentService handles communication with the component related methods of the Backstage Catalog API.
 type componentService typedEntityService[ComponentEntityV1alpha1]
 
@@ -67,3 +104,21 @@ func (s *componentService) Get(ctx context.Context, n string, ns string) (*Compo
 	cs := (typedEntityService[ComponentEntityV1alpha1])(*s)
 	return cs.get(ctx, KindComponent, n, ns)
 }
+
+// List returns a list of component entities. It can optionally be filtered by a set of conditions and limited to a set of fields.
+func (s *componentService) List(ctx context.Context, options *ListEntityOptions) ([]ComponentEntityV1alpha1, *http.Response, error) {
+	cs := (typedEntityService[ComponentEntityV1alpha1])(*s)
+	return cs.list(ctx, KindComponent, options)
+}
+
+// ListAll pages through all component entities matching the options, calling fn for each page of at most pageSize entities.
+func (s *componentService) ListAll(ctx context.Context, options *ListEntityOptions, pageSize int, fn func(*EntityPage[ComponentEntityV1alpha1]) error) error {
+	cs := (typedEntityService[ComponentEntityV1alpha1])(*s)
+	return cs.listAll(ctx, KindComponent, options, pageSize, fn)
+}
+
+// Stream returns an iterator over all component entities matching the options, fetched in pages of at most pageSize entities.
+func (s *componentService) Stream(ctx context.Context, options *ListEntityOptions, pageSize int) iter.Seq2[ComponentEntityV1alpha1, error] {
+	cs := (typedEntityService[ComponentEntityV1alpha1])(*s)
+	return cs.stream(ctx, KindComponent, options, pageSize)
+}
@@ -2,6 +2,7 @@ package backstage
 
 import (
 	"context"
+	"iter"
 	"net/http"
 )
 
@@ -30,6 +31,12 @@ type DomainEntityV1alpha1Spec struct {
 	Owner string `json:"owner" yaml:"owner"`
 }
 
+// OwnerRef returns the parsed reference to the owner, with kind defaulting to "Group" and namespace to ns ("default", if not
+// specified). It returns nil if the reference is not set.
+func (s *DomainEntityV1alpha1Spec) OwnerRef(ns string) (*EntityRef, error) {
+	return parseOptionalRef(s.Owner, KindGroup, ns)
+}
+
 // domainService handles communication with the domain related methods of the Backstage Catalog API.
 type domainService typedEntityService[DomainEntityV1alpha1]
 
@@ -46,3 +53,21 @@ func (s *domainService) Get(ctx context.Context, n string, ns string) (*DomainEn
 	cs := (typedEntityService[DomainEntityV1alpha1])(*s)
 	return cs.get(ctx, KindDomain, n, ns)
 }
+
+// List returns a list of domain entities. It can optionally be filtered by a set of conditions and limited to a set of fields.
+func (s *domainService) List(ctx context.Context, options *ListEntityOptions) ([]DomainEntityV1alpha1, *http.Response, error) {
+	cs := (typedEntityService[DomainEntityV1alpha1])(*s)
+	return cs.list(ctx, KindDomain, options)
+}
+
+// ListAll pages through all domain entities matching the options, calling fn for each page of at most pageSize entities.
+func (s *domainService) ListAll(ctx context.Context, options *ListEntityOptions, pageSize int, fn func(*EntityPage[DomainEntityV1alpha1]) error) error {
+	cs := (typedEntityService[DomainEntityV1alpha1])(*s)
+	return cs.listAll(ctx, KindDomain, options, pageSize, fn)
+}
+
+// Stream returns an iterator over all domain entities matching the options, fetched in pages of at most pageSize entities.
+func (s *domainService) Stream(ctx context.Context, options *ListEntityOptions, pageSize int) iter.Seq2[DomainEntityV1alpha1, error] {
+	cs := (typedEntityService[DomainEntityV1alpha1])(*s)
+	return cs.stream(ctx, KindDomain, options, pageSize)
+}
@@ -2,6 +2,7 @@ package backstage
 
 import (
 	"context"
+	"iter"
 	"net/http"
 )
 
@@ -58,6 +59,24 @@ type GroupEntityV1alpha1Spec struct {
 	Members []string `json:"members,omitempty" yaml:"members,omitempty"`
 }
 
+// ParentRef returns the parsed reference to the parent group, with kind defaulting to "Group" and namespace to ns ("default", if
+// not specified). It returns nil if the reference is not set.
+func (s *GroupEntityV1alpha1Spec) ParentRef(ns string) (*EntityRef, error) {
+	return parseOptionalRef(s.Parent, KindGroup, ns)
+}
+
+// ChildrenRefs returns the parsed references to the child groups, with kind defaulting to "Group" and namespace to ns ("default",
+// if not specified).
+func (s *GroupEntityV1alpha1Spec) ChildrenRefs(ns string) ([]EntityRef, error) {
+	return parseRefs(s.Children, KindGroup, ns)
+}
+
+// MembersRefs returns the parsed references to the members, with kind defaulting to "User" and namespace to ns ("default", if not
+// specified).
+func (s *GroupEntityV1alpha1Spec) MembersRefs(ns string) ([]EntityRef, error) {
+	return parseRefs(s.Members, KindUser, ns)
+}
+
 // groupService handles communication with the group related methods of the Backstage Catalog API.
 type groupService typedEntityService[GroupEntityV1alpha1]
 
@@ -74,3 +93,21 @@ func (s *groupService) Get(ctx context.Context, n string, ns string) (*GroupEnti
 	cs := (typedEntityService[GroupEntityV1alpha1])(*s)
 	return cs.get(ctx, KindGroup, n, ns)
 }
+
+// List returns a list of group entities. It can optionally be filtered by a set of conditions and limited to a set of fields.
+func (s *groupService) List(ctx context.Context, options *ListEntityOptions) ([]GroupEntityV1alpha1, *http.Response, error) {
+	cs := (typedEntityService[GroupEntityV1alpha1])(*s)
+	return cs.list(ctx, KindGroup, options)
+}
+
+// ListAll pages through all group entities matching the options, calling fn for each page of at most pageSize entities.
+func (s *groupService) ListAll(ctx context.Context, options *ListEntityOptions, pageSize int, fn func(*EntityPage[GroupEntityV1alpha1]) error) error {
+	cs := (typedEntityService[GroupEntityV1alpha1])(*s)
+	return cs.listAll(ctx, KindGroup, options, pageSize, fn)
+}
+
+// Stream returns an iterator over all group entities matching the options, fetched in pages of at most pageSize entities.
+func (s *groupService) Stream(ctx context.Context, options *ListEntityOptions, pageSize int) iter.Seq2[GroupEntityV1alpha1, error] {
+	cs := (typedEntityService[GroupEntityV1alpha1])(*s)
+	return cs.stream(ctx, KindGroup, options, pageSize)
+}
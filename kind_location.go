@@ -4,13 +4,29 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"iter"
 	"net/http"
 	"net/url"
+	"strings"
 )
 
 // KindLocation defines name for location kind.
 const KindLocation = "Location"
 
+const (
+	// LocationTypeURL is the type of locations that are read from a URL.
+	LocationTypeURL = "url"
+
+	// LocationTypeFile is the type of locations that are read from the file system of the Backstage backend.
+	LocationTypeFile = "file"
+
+	// LocationPresenceRequired marks a location whose target must exist.
+	LocationPresenceRequired = "required"
+
+	// LocationPresenceOptional marks a location whose target may be missing without that being an error.
+	LocationPresenceOptional = "optional"
+)
+
 // LocationEntityV1alpha1 is a marker that references other places to look for catalog data.
 // https://github.com/backstage/backstage/blob/master/packages/catalog-model/src/schema/kinds/Location.v1alpha1.schema.json
 type LocationEntityV1alpha1 struct {
@@ -66,6 +82,55 @@ type LocationResponse struct {
 	Target string `json:"target" yaml:"target"`
 }
 
+// LocationSpec describes a location by its type and target.
+type LocationSpec struct {
+	// Type of the location, e.g. "url".
+	Type string `json:"type" yaml:"type"`
+	// Target of the location.
+	Target string `json:"target" yaml:"target"`
+	// Presence describes whether the location target is required or optional.
+	Presence string `json:"presence,omitempty" yaml:"presence,omitempty"`
+}
+
+// LocationAnalyzeResponse defines POST response from analyze-location endpoint.
+type LocationAnalyzeResponse struct {
+	// ExistingEntityFiles contains catalog files that already exist in the analyzed location.
+	ExistingEntityFiles []LocationAnalyzeExistingEntity `json:"existingEntityFiles" yaml:"existingEntityFiles"`
+	// GenerateEntities contains entities that could be generated for the analyzed location.
+	GenerateEntities []LocationAnalyzeGenerateEntity `json:"generateEntities" yaml:"generateEntities"`
+}
+
+// LocationAnalyzeExistingEntity describes an entity found in an existing catalog file of the analyzed location.
+type LocationAnalyzeExistingEntity struct {
+	// Location of the catalog file the entity was found in.
+	Location LocationSpec `json:"location" yaml:"location"`
+	// IsRegistered is true if the location is already registered in the catalog.
+	IsRegistered bool `json:"isRegistered" yaml:"isRegistered"`
+	// Entity found in the catalog file.
+	Entity Entity `json:"entity" yaml:"entity"`
+}
+
+// LocationAnalyzeGenerateEntity describes an entity suggested for the analyzed location.
+type LocationAnalyzeGenerateEntity struct {
+	// Entity is a partial entity, containing only the fields that could be inferred from the location.
+	Entity Entity `json:"entity" yaml:"entity"`
+	// Fields contains suggestions for the fields of the generated entity.
+	Fields []LocationAnalyzeField `json:"fields" yaml:"fields"`
+}
+
+// LocationAnalyzeField describes a suggestion for a single field of a generated entity.
+type LocationAnalyzeField struct {
+	// Field is a path to the field, e.g. "spec.owner".
+	Field string `json:"field" yaml:"field"`
+	// State of the suggestion.
+	// Either ["analysisSuggestedValue", "analysisSuggestedNoValue", "needsUserInput"]
+	State string `json:"state" yaml:"state"`
+	// Value suggested for the field, if any.
+	Value *string `json:"value" yaml:"value"`
+	// Description explains the suggestion.
+	Description string `json:"description" yaml:"description"`
+}
+
 // LocationListResponse defines GET response to get all locations from location endpoints.
 type LocationListResponse struct {
 	Data *LocationResponse `json:"data" yaml:"data"`
@@ -88,29 +153,55 @@ func (s *locationService) Get(ctx context.Context, n string, ns string) (*Locati
 	return cs.get(ctx, KindLocation, n, ns)
 }
 
-// Create creates a new location.
+// ListEntities returns a list of location entities. It can optionally be filtered by a set of conditions and limited to a set of
+// fields. Unlike List, it returns Location kind entities rather than the registered locations.
+func (s *locationService) ListEntities(ctx context.Context, options *ListEntityOptions) ([]LocationEntityV1alpha1, *http.Response, error) {
+	cs := (typedEntityService[LocationEntityV1alpha1])(*s)
+	return cs.list(ctx, KindLocation, options)
+}
+
+// ListAllEntities pages through all location entities matching the options, calling fn for each page of at most pageSize entities.
+func (s *locationService) ListAllEntities(ctx context.Context, options *ListEntityOptions, pageSize int, fn func(*EntityPage[LocationEntityV1alpha1]) error) error {
+	cs := (typedEntityService[LocationEntityV1alpha1])(*s)
+	return cs.listAll(ctx, KindLocation, options, pageSize, fn)
+}
+
+// StreamEntities returns an iterator over all location entities matching the options, fetched in pages of at most pageSize entities.
+func (s *locationService) StreamEntities(ctx context.Context, options *ListEntityOptions, pageSize int) iter.Seq2[LocationEntityV1alpha1, error] {
+	cs := (typedEntityService[LocationEntityV1alpha1])(*s)
+	return cs.stream(ctx, KindLocation, options, pageSize)
+}
+
+// Create creates a new location of "url" type.
 func (s *locationService) Create(ctx context.Context, target string, dryRun bool) (*LocationCreateResponse, *http.Response, error) {
-	if target == "" {
+	return s.CreateFromSpec(ctx, LocationSpec{Type: LocationTypeURL, Target: target}, dryRun)
+}
+
+// CreateFromSpec creates a new location of any type (e.g. "url", "file" or a custom provider type) and presence. If location type
+// is not specified, "url" is used.
+func (s *locationService) CreateFromSpec(ctx context.Context, location LocationSpec, dryRun bool) (*LocationCreateResponse, *http.Response, error) {
+	if location.Target == "" {
 		return nil, nil, errors.New("target cannot be empty")
 	}
 
+	if location.Type == "" {
+		location.Type = LocationTypeURL
+	}
+
+	if location.Presence != "" && location.Presence != LocationPresenceRequired && location.Presence != LocationPresenceOptional {
+		return nil, nil, fmt.Errorf("invalid location presence: %s", location.Presence)
+	}
+
 	path, _ := url.JoinPath(s.apiPath, "../locations")
-	req, _ := s.client.newRequest(http.MethodPost, fmt.Sprintf("%s?dryRun=%t", path, dryRun), struct {
-		Target string `json:"target" yaml:"target"`
-		Type   string `json:"type" yaml:"type"`
-	}{
-		Target: target,
-		Type:   "url",
-	})
+	req, _ := s.client.newRequest(http.MethodPost, fmt.Sprintf("%s?dryRun=%t", path, dryRun), location)
 
 	var entity *LocationCreateResponse
 	resp, err := s.client.do(ctx, req, &entity)
 
 	return entity, resp, err
-
 }
 
-// List returns all locations.
+// List returns all locations, each wrapped in LocationListResponse. Use ListFlat to get the locations without the wrapper.
 func (s *locationService) List(ctx context.Context) ([]LocationListResponse, *http.Response, error) {
 	path, _ := url.JoinPath(s.apiPath, "../locations")
 	req, _ := s.client.newRequest(http.MethodGet, path, nil)
@@ -121,6 +212,39 @@ func (s *locationService) List(ctx context.Context) ([]LocationListResponse, *ht
 	return entities, resp, err
 }
 
+// ListFlat returns all locations.
+func (s *locationService) ListFlat(ctx context.Context) ([]LocationResponse, *http.Response, error) {
+	list, resp, err := s.List(ctx)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	locations := make([]LocationResponse, 0, len(list))
+	for _, l := range list {
+		if l.Data != nil {
+			locations = append(locations, *l.Data)
+		}
+	}
+
+	return locations, resp, nil
+}
+
+// GetByEntity returns a location that manages the entity identified by the kind, name and the namespace ("default", if not
+// specified) it belongs to.
+func (s *locationService) GetByEntity(ctx context.Context, kind string, ns string, n string) (*LocationResponse, *http.Response, error) {
+	if ns == "" {
+		ns = s.client.DefaultNamespace
+	}
+
+	path, _ := url.JoinPath(s.apiPath, "../locations/by-entity", strings.ToLower(kind), ns, n)
+	req, _ := s.client.newRequest(http.MethodGet, path, nil)
+
+	var entity *LocationResponse
+	resp, err := s.client.do(ctx, req, &entity)
+
+	return entity, resp, err
+}
+
 // GetByID returns a location identified by its ID.
 func (s *locationService) GetByID(ctx context.Context, id string) (*LocationResponse, *http.Response, error) {
 	path, _ := url.JoinPath(s.apiPath, "../locations", id)
@@ -132,6 +256,32 @@ func (s *locationService) GetByID(ctx context.Context, id string) (*LocationResp
 	return entity, resp, err
 }
 
+// Analyze analyzes a location, returning the catalog files already present in it and the entities that could be generated for it.
+// If location type is not specified, "url" is used. Optional catalogFilename overrides the name of the catalog file to look for.
+func (s *locationService) Analyze(ctx context.Context, location LocationSpec, catalogFilename string) (*LocationAnalyzeResponse, *http.Response, error) {
+	if location.Target == "" {
+		return nil, nil, errors.New("target cannot be empty")
+	}
+
+	if location.Type == "" {
+		location.Type = LocationTypeURL
+	}
+
+	path, _ := url.JoinPath(s.apiPath, "../analyze-location")
+	req, _ := s.client.newRequest(http.MethodPost, path, struct {
+		Location        LocationSpec `json:"location" yaml:"location"`
+		CatalogFilename string       `json:"catalogFilename,omitempty" yaml:"catalogFilename,omitempty"`
+	}{
+		Location:        location,
+		CatalogFilename: catalogFilename,
+	})
+
+	var result *LocationAnalyzeResponse
+	resp, err := s.client.do(ctx, req, &result)
+
+	return result, resp, err
+}
+
 // DeleteByID deletes a location identified by its ID.
 func (s *locationService) DeleteByID(ctx context.Context, id string) (*http.Response, error) {
 	if id == "" {
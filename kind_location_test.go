@@ -210,3 +210,210 @@ func TestKindLocationDeleteByID(t *testing.T) {
 	assert.NotEmpty(t, resp, "Response should not be empty")
 	assert.EqualValues(t, http.StatusNoContent, resp.StatusCode, "Response status code should match the one from the server")
 }
+
+// TestKindLocationAnalyze tests functionality of analyzing a location.
+func TestKindLocationAnalyze(t *testing.T) {
+	const dataFile = "testdata/location_analyze.json"
+	const target = "https://github.com/datolabs-io/go-backstage"
+
+	expected := LocationAnalyzeResponse{}
+	expectedData, _ := os.ReadFile(dataFile)
+	err := json.Unmarshal(expectedData, &expected)
+
+	assert.FileExists(t, dataFile, "Test data file should exist")
+	assert.NoError(t, err, "Unmarshal should not return an error")
+
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Post("/catalog/analyze-location").
+		JSON(map[string]interface{}{
+			"location": map[string]string{
+				"type":   "url",
+				"target": target,
+			},
+			"catalogFilename": "catalog.yaml",
+		}).
+		Reply(200).
+		File(dataFile)
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	s := newLocationService(&entityService{
+		client:  c,
+		apiPath: "/catalog/entities",
+	})
+
+	actual, resp, err := s.Analyze(context.Background(), LocationSpec{Target: target}, "catalog.yaml")
+	assert.NoError(t, err, "Analyze should not return an error")
+	assert.NotEmpty(t, resp, "Response should not be empty")
+	assert.EqualValues(t, &expected, actual, "Response body should match the one from the server")
+	assert.Len(t, actual.ExistingEntityFiles, 1, "Existing entity files should be decoded")
+	assert.Nil(t, actual.GenerateEntities[0].Fields[0].Value, "Missing suggestion value should be nil")
+}
+
+// TestKindLocationAnalyze_EmptyTarget tests if an error is returned when analyzing a location without target.
+func TestKindLocationAnalyze_EmptyTarget(t *testing.T) {
+	c, _ := NewClient("", "", nil)
+	s := newLocationService(&entityService{
+		client:  c,
+		apiPath: "/catalog/entities",
+	})
+
+	_, _, err := s.Analyze(context.Background(), LocationSpec{}, "")
+	assert.Error(t, err, "Analyze should return an error when the target is empty")
+}
+
+// TestKindLocationCreateFromSpec tests functionality of creating a new location of non-url type.
+func TestKindLocationCreateFromSpec(t *testing.T) {
+	const target = "/var/lib/backstage/catalog-info.yaml"
+
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Post("/catalog/locations").
+		MatchParam("dryRun", "false").
+		JSON(map[string]string{
+			"type":     LocationTypeFile,
+			"target":   target,
+			"presence": LocationPresenceOptional,
+		}).
+		Reply(201).
+		JSON(&LocationCreateResponse{
+			Location: &LocationResponse{
+				ID:     "830d2354-8bbb-42d1-a751-2959f6da5416",
+				Type:   LocationTypeFile,
+				Target: target,
+			},
+			Entities: []Entity{},
+		})
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	s := newLocationService(&entityService{
+		client:  c,
+		apiPath: "/catalog/entities",
+	})
+
+	actual, resp, err := s.CreateFromSpec(context.Background(), LocationSpec{
+		Type:     LocationTypeFile,
+		Target:   target,
+		Presence: LocationPresenceOptional,
+	}, false)
+	assert.NoError(t, err, "Create should not return an error")
+	assert.NotEmpty(t, resp, "Response should not be empty")
+	assert.Equal(t, LocationTypeFile, actual.Location.Type, "Created location type should match the requested one")
+}
+
+// TestKindLocationCreateFromSpec_InvalidPresence tests if an error is returned when location presence is invalid.
+func TestKindLocationCreateFromSpec_InvalidPresence(t *testing.T) {
+	c, _ := NewClient("", "", nil)
+	s := newLocationService(&entityService{
+		client:  c,
+		apiPath: "/catalog/entities",
+	})
+
+	_, _, err := s.CreateFromSpec(context.Background(), LocationSpec{Target: "foo", Presence: "sometimes"}, true)
+	assert.Error(t, err, "Create should return an error when the presence is invalid")
+}
+
+// TestKindLocationListFlat tests functionality of getting all locations without the data wrapper.
+func TestKindLocationListFlat(t *testing.T) {
+	const dataFile = "testdata/locations.json"
+
+	var list []LocationListResponse
+	expectedData, _ := os.ReadFile(dataFile)
+	err := json.Unmarshal(expectedData, &list)
+
+	assert.FileExists(t, dataFile, "Test data file should exist")
+	assert.NoError(t, err, "Unmarshal should not return an error")
+
+	var expected []LocationResponse
+	for _, l := range list {
+		expected = append(expected, *l.Data)
+	}
+
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/locations").
+		Reply(200).
+		File(dataFile)
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	s := newLocationService(&entityService{
+		client:  c,
+		apiPath: "/catalog/entities",
+	})
+
+	actual, resp, err := s.ListFlat(context.Background())
+	assert.NoError(t, err, "List should not return an error")
+	assert.NotEmpty(t, resp, "Response should not be empty")
+	assert.EqualValues(t, expected, actual, "Response body should match the one from the server")
+}
+
+// TestKindLocationGetByEntity tests functionality of getting a location by the entity it manages.
+func TestKindLocationGetByEntity(t *testing.T) {
+	const dataFile = "testdata/location_by_id.json"
+
+	expected := LocationResponse{}
+	expectedData, _ := os.ReadFile(dataFile)
+	err := json.Unmarshal(expectedData, &expected)
+
+	assert.FileExists(t, dataFile, "Test data file should exist")
+	assert.NoError(t, err, "Unmarshal should not return an error")
+
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/locations/by-entity/component/default/example-website").
+		Reply(200).
+		File(dataFile)
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	s := newLocationService(&entityService{
+		client:  c,
+		apiPath: "/catalog/entities",
+	})
+
+	actual, resp, err := s.GetByEntity(context.Background(), KindComponent, "", "example-website")
+	assert.NoError(t, err, "Get should not return an error")
+	assert.NotEmpty(t, resp, "Response should not be empty")
+	assert.EqualValues(t, &expected, actual, "Response body should match the one from the server")
+}
+
+// TestKindLocationListEntities tests functionality of listing location entities.
+func TestKindLocationListEntities(t *testing.T) {
+	const dataFile = "testdata/location.json"
+
+	expected := LocationEntityV1alpha1{}
+	expectedData, _ := os.ReadFile(dataFile)
+	err := json.Unmarshal(expectedData, &expected)
+
+	assert.FileExists(t, dataFile, "Test data file should exist")
+	assert.NoError(t, err, "Unmarshal should not return an error")
+
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("filter", "kind=location,metadata.namespace=default").
+		Reply(200).
+		JSON([]json.RawMessage{expectedData})
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	s := newLocationService(&entityService{
+		client:  c,
+		apiPath: "/catalog/entities",
+	})
+
+	actual, resp, err := s.ListEntities(context.Background(), &ListEntityOptions{
+		Filters: []string{"metadata.namespace=default"},
+	})
+	assert.NoError(t, err, "List should not return an error")
+	assert.NotEmpty(t, resp, "Response should not be empty")
+	assert.EqualValues(t, []LocationEntityV1alpha1{expected}, actual, "Response body should match the one from the server")
+}
@@ -0,0 +1,165 @@
+package backstage
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ApiVersionV1alpha1 is the API version of the built-in kinds.
+const ApiVersionV1alpha1 = "backstage.io/v1alpha1"
+
+// kindRegistration describes a kind registered with RegisterKind.
+type kindRegistration struct {
+	apiVersion string
+	kind       string
+	factory    func() TypedEntity
+	typ        reflect.Type
+}
+
+// kindRegistry contains all registered kinds, keyed by the lowercase kind name, and indexed by the type of their entities.
+var kindRegistry = struct {
+	sync.RWMutex
+	kinds map[string][]kindRegistration
+	types map[reflect.Type]kindRegistration
+}{
+	kinds: map[string][]kindRegistration{},
+	types: map[reflect.Type]kindRegistration{},
+}
+
+// KindService handles communication with the Backstage Catalog API for entities of any registered kind.
+type KindService[T any] struct {
+	s    typedEntityService[T]
+	kind string
+}
+
+func init() {
+	RegisterKind(ApiVersionV1alpha1, KindAPI, func() TypedEntity { return &ApiEntityV1alpha1{} })
+	RegisterKind(ApiVersionV1alpha1, KindComponent, func() TypedEntity { return &ComponentEntityV1alpha1{} })
+	RegisterKind(ApiVersionV1alpha1, KindDomain, func() TypedEntity { return &DomainEntityV1alpha1{} })
+	RegisterKind(ApiVersionV1alpha1, KindGroup, func() TypedEntity { return &GroupEntityV1alpha1{} })
+	RegisterKind(ApiVersionV1alpha1, KindLocation, func() TypedEntity { return &LocationEntityV1alpha1{} })
+	RegisterKind(ApiVersionV1alpha1, KindResource, func() TypedEntity { return &ResourceEntityV1alpha1{} })
+	RegisterKind(ApiVersionV1alpha1, KindSystem, func() TypedEntity { return &SystemEntityV1alpha1{} })
+	RegisterKind(ApiVersionV1alpha1, KindUser, func() TypedEntity { return &UserEntityV1alpha1{} })
+	RegisterKind(ApiVersionScaffolderV1beta3, KindTemplate, func() TypedEntity { return &TemplateEntityV1beta3{} })
+}
+
+// RegisterKind registers a typed kind, so that entities of the given API version and kind are decoded by Entity.Typed into the
+// value returned by factory, and can be accessed through a KindService. The factory must return a pointer to a struct embedding
+// Entity, e.g. &DatabaseEntityV1alpha1{}. An empty apiVersion matches entities of the kind with any API version. Registering the
+// same API version and kind again replaces the previous registration, while registering a type already registered for another
+// API version or kind panics.
+func RegisterKind(apiVersion string, kind string, factory func() TypedEntity) {
+	if kind == "" || factory == nil {
+		panic("backstage: RegisterKind requires a kind and a factory")
+	}
+
+	typ := reflect.TypeOf(factory())
+
+	kindRegistry.Lock()
+	defer kindRegistry.Unlock()
+
+	key := strings.ToLower(kind)
+	if r, ok := kindRegistry.types[typ]; ok && (r.apiVersion != apiVersion || strings.ToLower(r.kind) != key) {
+		panic(fmt.Sprintf("backstage: %s is already registered for kind %s of API version %q", typ, r.kind, r.apiVersion))
+	}
+
+	registrations := kindRegistry.kinds[key]
+	for i, r := range registrations {
+		if r.apiVersion == apiVersion {
+			delete(kindRegistry.types, r.typ)
+			registrations[i].factory, registrations[i].typ = factory, typ
+			kindRegistry.types[typ] = registrations[i]
+			return
+		}
+	}
+
+	r := kindRegistration{
+		apiVersion: apiVersion,
+		kind:       kind,
+		factory:    factory,
+		typ:        typ,
+	}
+	kindRegistry.kinds[key] = append(registrations, r)
+	kindRegistry.types[typ] = r
+}
+
+// lookupKind returns the factory registered for the API version and kind, or nil if there is none. A registration without API
+// version is used if no exact match exists; if the API version is not known either, the first registration of the kind is used.
+func lookupKind(apiVersion string, kind string) func() TypedEntity {
+	kindRegistry.RLock()
+	defer kindRegistry.RUnlock()
+
+	registrations := kindRegistry.kinds[strings.ToLower(kind)]
+
+	var wildcard func() TypedEntity
+	for _, r := range registrations {
+		switch r.apiVersion {
+		case apiVersion:
+			return r.factory
+		case "":
+			wildcard = r.factory
+		}
+	}
+
+	if wildcard == nil && apiVersion == "" && len(registrations) > 0 {
+		return registrations[0].factory
+	}
+
+	return wildcard
+}
+
+// lookupType returns the registration of the kind registered for the typed entity type.
+func lookupType(typ reflect.Type) (kindRegistration, bool) {
+	kindRegistry.RLock()
+	defer kindRegistry.RUnlock()
+
+	r, ok := kindRegistry.types[typ]
+
+	return r, ok
+}
+
+// Kind returns a service for entities of the typed kind T, which must be registered with RegisterKind, e.g.
+// backstage.Kind[DatabaseEntityV1alpha1](client.Catalog).
+func Kind[T any, PT interface {
+	*T
+	TypedEntity
+}](c *catalogService) (*KindService[T], error) {
+	r, ok := lookupType(reflect.TypeOf(PT(nil)))
+	if !ok {
+		return nil, fmt.Errorf("kind is not registered: %T", *new(T))
+	}
+
+	return &KindService[T]{
+		s: typedEntityService[T]{
+			client:  c.Entities.client,
+			apiPath: c.Entities.apiPath,
+		},
+		kind: r.kind,
+	}, nil
+}
+
+// Get returns an entity identified by the name and the namespace ("default", if not specified) it belongs to.
+func (s *KindService[T]) Get(ctx context.Context, n string, ns string) (*T, *http.Response, error) {
+	return s.s.get(ctx, s.kind, n, ns)
+}
+
+// List returns a list of entities. It can optionally be filtered by a set of conditions and limited to a set of fields.
+func (s *KindService[T]) List(ctx context.Context, options *ListEntityOptions) ([]T, *http.Response, error) {
+	return s.s.list(ctx, s.kind, options)
+}
+
+// ListAll pages through all entities matching the options, calling fn for each page of at most pageSize entities.
+func (s *KindService[T]) ListAll(ctx context.Context, options *ListEntityOptions, pageSize int, fn func(*EntityPage[T]) error) error {
+	return s.s.listAll(ctx, s.kind, options, pageSize, fn)
+}
+
+// Stream returns an iterator over all entities matching the options, fetched in pages of at most pageSize entities.
+func (s *KindService[T]) Stream(ctx context.Context, options *ListEntityOptions, pageSize int) iter.Seq2[T, error] {
+	return s.s.stream(ctx, s.kind, options, pageSize)
+}
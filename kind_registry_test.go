@@ -0,0 +1,131 @@
+package backstage
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+)
+
+// databaseEntity is a custom kind used for testing the kind registry.
+type databaseEntity struct {
+	Entity
+
+	ApiVersion string `json:"apiVersion"`
+
+	Kind string `json:"kind"`
+
+	Spec *struct {
+		Engine string `json:"engine"`
+	} `json:"spec"`
+}
+
+// unregisteredEntity is a custom kind that is never registered.
+type unregisteredEntity struct {
+	Entity
+}
+
+func init() {
+	RegisterKind("acme.com/v1", "Database", func() TypedEntity { return &databaseEntity{} })
+}
+
+// TestRegisterKind tests that entities of a registered custom kind are decoded into the typed kind.
+func TestRegisterKind(t *testing.T) {
+	actual, err := Entity{
+		ApiVersion: "acme.com/v1",
+		Kind:       "database",
+		Metadata:   EntityMeta{Name: "orders"},
+		Spec:       map[string]interface{}{"engine": "postgres"},
+	}.Typed()
+
+	assert.NoError(t, err, "Typed should not return an error")
+	if assert.IsType(t, &databaseEntity{}, actual, "Entity should be decoded into the custom kind") {
+		assert.Equal(t, "postgres", actual.(*databaseEntity).Spec.Engine, "Spec should be decoded")
+	}
+
+	actual, err = Entity{ApiVersion: "acme.com/v2", Kind: "Database"}.Typed()
+	assert.NoError(t, err, "Typed should not return an error")
+	assert.IsType(t, &Entity{}, actual, "Entity of unregistered API version should not be converted")
+}
+
+// TestRegisterKind_DuplicateType tests that a type cannot be registered for more than one kind.
+func TestRegisterKind_DuplicateType(t *testing.T) {
+	factory := func() TypedEntity { return &databaseEntity{} }
+
+	assert.NotPanics(t, func() { RegisterKind("acme.com/v1", "database", factory) }, "Registering the same kind again should not panic")
+	assert.Panics(t, func() { RegisterKind("acme.com/v1", "Table", factory) }, "Registering the type for another kind should panic")
+	assert.Panics(t, func() { RegisterKind("acme.com/v2", "Database", factory) }, "Registering the type for another API version should panic")
+
+	r, ok := lookupType(reflect.TypeOf(&databaseEntity{}))
+	assert.True(t, ok, "Type should stay registered")
+	assert.Equal(t, "Database", r.kind, "Type should stay registered for the original kind")
+}
+
+// TestKind tests the retrieval of entities of a registered custom kind.
+func TestKind(t *testing.T) {
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities/by-name/database/default/orders").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"apiVersion": "acme.com/v1",
+			"kind":       "Database",
+			"metadata":   map[string]string{"name": "orders"},
+			"spec":       map[string]string{"engine": "postgres"},
+		})
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("filter", "kind=database").
+		Reply(200).
+		JSON([]map[string]interface{}{{
+			"apiVersion": "acme.com/v1",
+			"kind":       "Database",
+			"metadata":   map[string]string{"name": "orders"},
+			"spec":       map[string]string{"engine": "mysql"},
+		}})
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	s, err := Kind[databaseEntity](c.Catalog)
+	assert.NoError(t, err, "Kind should not return an error")
+
+	entity, resp, err := s.Get(context.Background(), "orders", "")
+	assert.NoError(t, err, "Get should not return an error")
+	assert.NotEmpty(t, resp, "Response should not be empty")
+	assert.Equal(t, "postgres", entity.Spec.Engine, "Spec should be decoded")
+
+	entities, _, err := s.List(context.Background(), nil)
+	assert.NoError(t, err, "List should not return an error")
+	assert.Len(t, entities, 1, "List should return all entities")
+	assert.Equal(t, "mysql", entities[0].Spec.Engine, "Spec should be decoded")
+}
+
+// TestKind_Unregistered tests if an error is returned when the kind is not registered.
+func TestKind_Unregistered(t *testing.T) {
+	c, _ := NewClient("", "", nil)
+	_, err := Kind[unregisteredEntity](c.Catalog)
+	assert.Error(t, err, "Kind should return an error when the kind is not registered")
+}
+
+// TestEntityServiceListTyped tests the retrieval of a list of entities converted to typed kinds.
+func TestEntityServiceListTyped(t *testing.T) {
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		Reply(200).
+		File("testdata/entities.json")
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+
+	actual, _, err := c.Catalog.Entities.ListTyped(context.Background(), nil)
+	assert.NoError(t, err, "ListTyped should not return an error")
+	assert.IsType(t, &ComponentEntityV1alpha1{}, actual[0], "Component should be converted")
+	assert.IsType(t, &ApiEntityV1alpha1{}, actual[1], "API should be converted")
+}
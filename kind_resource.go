@@ -2,6 +2,7 @@ package backstage
 
 import (
 	"context"
+	"iter"
 	"net/http"
 )
 
@@ -39,6 +40,24 @@ type ResourceEntityV1alpha1Spec struct {
 	System string `json:"system,omitempty" yaml:"system,omitempty"`
 }
 
+// OwnerRef returns the parsed reference to the owner, with kind defaulting to "Group" and namespace to ns ("default", if not
+// specified). It returns nil if the reference is not set.
+func (s *ResourceEntityV1alpha1Spec) OwnerRef(ns string) (*EntityRef, error) {
+	return parseOptionalRef(s.Owner, KindGroup, ns)
+}
+
+// DependsOnRefs returns the parsed references to the dependencies, with namespace defaulting to ns ("default", if not specified);
+// the kind is required.
+func (s *ResourceEntityV1alpha1Spec) DependsOnRefs(ns string) ([]EntityRef, error) {
+	return parseRefs(s.DependsOn, "", ns)
+}
+
+// SystemRef returns the parsed reference to the system, with kind defaulting to "System" and namespace to ns ("default", if not
+// specified). It returns nil if the reference is not set.
+func (s *ResourceEntityV1alpha1Spec) SystemRef(ns string) (*EntityRef, error) {
+	return parseOptionalRef(s.System, KindSystem, ns)
+}
+
 // resourceService handles communication with the resource related methods of the Backstage Catalog API.
 type resourceService typedEntityService[ResourceEntityV1alpha1]
 
@@ -55,3 +74,21 @@ func (s *resourceService) Get(ctx context.Context, n string, ns string) (*Resour
 	cs := (typedEntityService[ResourceEntityV1alpha1])(*s)
 	return cs.get(ctx, KindResource, n, ns)
 }
+
+// List returns a list of resource entities. It can optionally be filtered by a set of conditions and limited to a set of fields.
+func (s *resourceService) List(ctx context.Context, options *ListEntityOptions) ([]ResourceEntityV1alpha1, *http.Response, error) {
+	cs := (typedEntityService[ResourceEntityV1alpha1])(*s)
+	return cs.list(ctx, KindResource, options)
+}
+
+// ListAll pages through all resource entities matching the options, calling fn for each page of at most pageSize entities.
+func (s *resourceService) ListAll(ctx context.Context, options *ListEntityOptions, pageSize int, fn func(*EntityPage[ResourceEntityV1alpha1]) error) error {
+	cs := (typedEntityService[ResourceEntityV1alpha1])(*s)
+	return cs.listAll(ctx, KindResource, options, pageSize, fn)
+}
+
+// Stream returns an iterator over all resource entities matching the options, fetched in pages of at most pageSize entities.
+func (s *resourceService) Stream(ctx context.Context, options *ListEntityOptions, pageSize int) iter.Seq2[ResourceEntityV1alpha1, error] {
+	cs := (typedEntityService[ResourceEntityV1alpha1])(*s)
+	return cs.stream(ctx, KindResource, options, pageSize)
+}
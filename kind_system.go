@@ -2,6 +2,7 @@ package backstage
 
 import (
 	"context"
+	"iter"
 	"net/http"
 )
 
@@ -34,6 +35,18 @@ type SystemEntityV1alpha1Spec struct {
 	Domain string `json:"domain,omitempty" yaml:"domain,omitempty"`
 }
 
+// OwnerRef returns the parsed reference to the owner, with kind defaulting to "Group" and namespace to ns ("default", if not
+// specified). It returns nil if the reference is not set.
+func (s *SystemEntityV1alpha1Spec) OwnerRef(ns string) (*EntityRef, error) {
+	return parseOptionalRef(s.Owner, KindGroup, ns)
+}
+
+// DomainRef returns the parsed reference to the domain, with kind defaulting to "Domain" and namespace to ns ("default", if not
+// specified). It returns nil if the reference is not set.
+func (s *SystemEntityV1alpha1Spec) DomainRef(ns string) (*EntityRef, error) {
+	return parseOptionalRef(s.Domain, KindDomain, ns)
+}
+
 // systemService handles communication with the system methods of the Backstage Catalog API.
 type systemService typedEntityService[SystemEntityV1alpha1]
 
@@ -50,3 +63,21 @@ func (s *systemService) Get(ctx context.Context, n string, ns string) (*SystemEn
 	cs := (typedEntityService[SystemEntityV1alpha1])(*s)
 	return cs.get(ctx, KindSystem, n, ns)
 }
+
+// List returns a list of system entities. It can optionally be filtered by a set of conditions and limited to a set of fields.
+func (s *systemService) List(ctx context.Context, options *ListEntityOptions) ([]SystemEntityV1alpha1, *http.Response, error) {
+	cs := (typedEntityService[SystemEntityV1alpha1])(*s)
+	return cs.list(ctx, KindSystem, options)
+}
+
+// ListAll pages through all system entities matching the options, calling fn for each page of at most pageSize entities.
+func (s *systemService) ListAll(ctx context.Context, options *ListEntityOptions, pageSize int, fn func(*EntityPage[SystemEntityV1alpha1]) error) error {
+	cs := (typedEntityService[SystemEntityV1alpha1])(*s)
+	return cs.listAll(ctx, KindSystem, options, pageSize, fn)
+}
+
+// Stream returns an iterator over all system entities matching the options, fetched in pages of at most pageSize entities.
+func (s *systemService) Stream(ctx context.Context, options *ListEntityOptions, pageSize int) iter.Seq2[SystemEntityV1alpha1, error] {
+	cs := (typedEntityService[SystemEntityV1alpha1])(*s)
+	return cs.stream(ctx, KindSystem, options, pageSize)
+}
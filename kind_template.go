@@ -0,0 +1,287 @@
+package backstage
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"net/http"
+	"reflect"
+)
+
+// KindTemplate defines name for template kind.
+const KindTemplate = "Template"
+
+// ApiVersionScaffolderV1beta3 is the API version of the template kind.
+const ApiVersionScaffolderV1beta3 = "scaffolder.backstage.io/v1beta3"
+
+// TemplateEntityV1beta3 describes a software template, used by the scaffolder to create new components. It defines the
+// parameters collected from the user and the steps executed with them.
+// https://github.com/backstage/backstage/blob/master/plugins/scaffolder-common/src/Template.v1beta3.schema.json
+type TemplateEntityV1beta3 struct {
+	Entity
+
+	// ApiVersion is always "scaffolder.backstage.io/v1beta3".
+	ApiVersion string `json:"apiVersion" yaml:"apiVersion"`
+
+	// Kind is always "Template".
+	Kind string `json:"kind" yaml:"kind"`
+
+	// Spec is the specification data describing the template itself.
+	Spec *TemplateEntityV1beta3Spec `json:"spec" yaml:"spec"`
+}
+
+// TemplateEntityV1beta3Spec describes the specification data describing the template itself.
+type TemplateEntityV1beta3Spec struct {
+	// Type of component created by the template, e.g. "service" or "website".
+	Type string `json:"type" yaml:"type"`
+
+	// Owner is an entity reference to the owner of the template.
+	Owner string `json:"owner,omitempty" yaml:"owner,omitempty"`
+
+	// Parameters contains the steps of the form presented to the user. Each of them is a JSON Schema fragment describing the
+	// values collected in that step. Parameters given as a single object are encoded as a single object again, unless further
+	// steps are added.
+	Parameters TemplateParameters `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+
+	// Steps is a list of actions executed by the scaffolder when the template is run.
+	Steps []TemplateStep `json:"steps" yaml:"steps"`
+
+	// Output describes what is presented to the user once the template has been run.
+	Output *TemplateOutput `json:"output,omitempty" yaml:"output,omitempty"`
+}
+
+// OwnerRef returns the parsed reference to the owner, with kind defaulting to "Group" and namespace to ns ("default", if not
+// specified). It returns nil if the reference is not set.
+func (s *TemplateEntityV1beta3Spec) OwnerRef(ns string) (*EntityRef, error) {
+	return parseOptionalRef(s.Owner, KindGroup, ns)
+}
+
+// TemplateParameters is a list of parameter steps of a template.
+type TemplateParameters []TemplateParameter
+
+// TemplateParameter is a JSON Schema fragment describing the values collected in a single step of the template form. Keywords
+// not modelled by the fields, e.g. "type", "ui:order", "allOf" or "if", are kept in Extra.
+type TemplateParameter struct {
+	// Title of the form step.
+	Title string `json:"title,omitempty" yaml:"title,omitempty"`
+
+	// Description of the form step.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	// Required contains names of the properties that must be provided.
+	Required []string `json:"required,omitempty" yaml:"required,omitempty"`
+
+	// Properties maps names of the collected values to their JSON Schema, including "ui:" options of the form fields.
+	Properties map[string]map[string]interface{} `json:"properties,omitempty" yaml:"properties,omitempty"`
+
+	// Dependencies describes properties that are shown or required depending on values of other properties.
+	Dependencies map[string]interface{} `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+
+	// Extra contains the keywords of the JSON Schema fragment not modelled by the fields above.
+	Extra map[string]interface{} `json:"-" yaml:",inline"`
+
+	// single is true if the parameter was decoded from parameters given as a single object.
+	single bool
+}
+
+// TemplateStep describes a single action executed by the scaffolder. Keys not modelled by the fields, e.g. "each", are kept in
+// Extra.
+type TemplateStep struct {
+	// ID of the step, used to reference its output in subsequent steps.
+	ID string `json:"id,omitempty" yaml:"id,omitempty"`
+
+	// Name of the step, presented to the user.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// Action is the ID of the scaffolder action to execute, e.g. "fetch:template".
+	Action string `json:"action" yaml:"action"`
+
+	// Input contains the values passed to the action.
+	Input map[string]interface{} `json:"input,omitempty" yaml:"input,omitempty"`
+
+	// If is a condition, either a boolean or a template expression string, that must be truthy for the step to be executed.
+	If interface{} `json:"if,omitempty" yaml:"if,omitempty"`
+
+	// Extra contains the keys of the step not modelled by the fields above.
+	Extra map[string]interface{} `json:"-" yaml:",inline"`
+}
+
+// TemplateOutput describes what is presented to the user once the template has been run.
+type TemplateOutput struct {
+	// Links is a list of links presented to the user.
+	Links []TemplateOutputLink `json:"links,omitempty" yaml:"links,omitempty"`
+
+	// Text is a list of text blocks presented to the user.
+	Text []TemplateOutputText `json:"text,omitempty" yaml:"text,omitempty"`
+}
+
+// TemplateOutputLink is a link presented to the user once the template has been run.
+type TemplateOutputLink struct {
+	// URL of the link.
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+
+	// EntityRef is a reference to an entity the link points to, used instead of the URL.
+	EntityRef string `json:"entityRef,omitempty" yaml:"entityRef,omitempty"`
+
+	// Title is a user-friendly display name for the link.
+	Title string `json:"title,omitempty" yaml:"title,omitempty"`
+
+	// Icon is a key representing a visual icon to be displayed in the UI.
+	Icon string `json:"icon,omitempty" yaml:"icon,omitempty"`
+}
+
+// TemplateOutputText is a text block presented to the user once the template has been run.
+type TemplateOutputText struct {
+	// Title of the text block.
+	Title string `json:"title,omitempty" yaml:"title,omitempty"`
+
+	// Icon is a key representing a visual icon to be displayed in the UI.
+	Icon string `json:"icon,omitempty" yaml:"icon,omitempty"`
+
+	// Content of the text block, in Markdown format.
+	Content string `json:"content,omitempty" yaml:"content,omitempty"`
+
+	// Default marks the text block to be shown by default.
+	Default bool `json:"default,omitempty" yaml:"default,omitempty"`
+}
+
+// UnmarshalJSON decodes the parameters from either a list of JSON Schema fragments or a single one.
+func (p *TemplateParameters) UnmarshalJSON(data []byte) error {
+	var list []TemplateParameter
+	if err := json.Unmarshal(data, &list); err == nil {
+		*p = list
+		return nil
+	}
+
+	var single TemplateParameter
+	if err := json.Unmarshal(data, &single); err != nil {
+		return err
+	}
+
+	single.single = true
+	*p = TemplateParameters{single}
+
+	return nil
+}
+
+// MarshalJSON encodes the parameters as a list, or as a single object if they were decoded from one.
+func (p TemplateParameters) MarshalJSON() ([]byte, error) {
+	if len(p) == 1 && p[0].single {
+		return json.Marshal(p[0])
+	}
+
+	return json.Marshal([]TemplateParameter(p))
+}
+
+// MarshalYAML encodes the parameters as a list, or as a single object if they were decoded from one.
+func (p TemplateParameters) MarshalYAML() (interface{}, error) {
+	if len(p) == 1 && p[0].single {
+		return p[0], nil
+	}
+
+	return []TemplateParameter(p), nil
+}
+
+// UnmarshalJSON decodes the parameter, keeping the keywords not modelled by its fields in Extra.
+func (p *TemplateParameter) UnmarshalJSON(data []byte) error {
+	type parameter TemplateParameter
+	return unmarshalWithExtra(data, (*parameter)(p), &p.Extra)
+}
+
+// MarshalJSON encodes the parameter, including the keywords in Extra.
+func (p TemplateParameter) MarshalJSON() ([]byte, error) {
+	type parameter TemplateParameter
+	return marshalWithExtra(parameter(p), p.Extra)
+}
+
+// UnmarshalJSON decodes the step, keeping the keys not modelled by its fields in Extra.
+func (s *TemplateStep) UnmarshalJSON(data []byte) error {
+	type step TemplateStep
+	return unmarshalWithExtra(data, (*step)(s), &s.Extra)
+}
+
+// MarshalJSON encodes the step, including the keys in Extra.
+func (s TemplateStep) MarshalJSON() ([]byte, error) {
+	type step TemplateStep
+	return marshalWithExtra(step(s), s.Extra)
+}
+
+// unmarshalWithExtra decodes the JSON object into the struct pointed to by v, and the keys not modelled by its fields into
+// extra.
+func unmarshalWithExtra(data []byte, v interface{}, extra *map[string]interface{}) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	var all map[string]interface{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+
+	for k := range jsonFieldTags(reflect.TypeOf(v).Elem()) {
+		delete(all, k)
+	}
+
+	*extra = nil
+	if len(all) > 0 {
+		*extra = all
+	}
+
+	return nil
+}
+
+// marshalWithExtra encodes the struct v as a JSON object, together with the extra keys not modelled by its fields.
+func marshalWithExtra(v interface{}, extra map[string]interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	var all map[string]interface{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	for k, x := range extra {
+		if _, ok := all[k]; !ok {
+			all[k] = x
+		}
+	}
+
+	return json.Marshal(all)
+}
+
+// templateService handles communication with the template related methods of the Backstage Catalog API.
+type templateService typedEntityService[TemplateEntityV1beta3]
+
+// newTemplateService returns a new instance of template-type entityService.
+func newTemplateService(s *entityService) *templateService {
+	return &templateService{
+		client:  s.client,
+		apiPath: s.apiPath,
+	}
+}
+
+// Get returns a template entity identified by the name and the namespace ("default", if not specified) it belongs to.
+func (s *templateService) Get(ctx context.Context, n string, ns string) (*TemplateEntityV1beta3, *http.Response, error) {
+	cs := (typedEntityService[TemplateEntityV1beta3])(*s)
+	return cs.get(ctx, KindTemplate, n, ns)
+}
+
+// List returns a list of template entities. It can optionally be filtered by a set of conditions and limited to a set of fields.
+func (s *templateService) List(ctx context.Context, options *ListEntityOptions) ([]TemplateEntityV1beta3, *http.Response, error) {
+	cs := (typedEntityService[TemplateEntityV1beta3])(*s)
+	return cs.list(ctx, KindTemplate, options)
+}
+
+// ListAll pages through all template entities matching the options, calling fn for each page of at most pageSize entities.
+func (s *templateService) ListAll(ctx context.Context, options *ListEntityOptions, pageSize int, fn func(*EntityPage[TemplateEntityV1beta3]) error) error {
+	cs := (typedEntityService[TemplateEntityV1beta3])(*s)
+	return cs.listAll(ctx, KindTemplate, options, pageSize, fn)
+}
+
+// Stream returns an iterator over all template entities matching the options, fetched in pages of at most pageSize entities.
+func (s *templateService) Stream(ctx context.Context, options *ListEntityOptions, pageSize int) iter.Seq2[TemplateEntityV1beta3, error] {
+	cs := (typedEntityService[TemplateEntityV1beta3])(*s)
+	return cs.stream(ctx, KindTemplate, options, pageSize)
+}
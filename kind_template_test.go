@@ -0,0 +1,150 @@
+package backstage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestKindTemplateGet tests functionality of getting a template.
+func TestKindTemplateGet(t *testing.T) {
+	const dataFile = "testdata/template.json"
+	const template = "example-nodejs-template"
+
+	expected := TemplateEntityV1beta3{}
+	expectedData, _ := os.ReadFile(dataFile)
+	err := json.Unmarshal(expectedData, &expected)
+
+	assert.FileExists(t, dataFile, "Test data file should exist")
+	assert.NoError(t, err, "Unmarshal should not return an error")
+
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get(fmt.Sprintf("/catalog/entities/by-name/template/default/%s", template)).
+		Reply(200).
+		File(dataFile)
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	s := newTemplateService(&entityService{
+		client:  c,
+		apiPath: "/catalog/entities",
+	})
+
+	actual, resp, err := s.Get(context.Background(), template, "")
+	assert.NoError(t, err, "Get should not return an error")
+	assert.NotEmpty(t, resp, "Response should not be empty")
+	assert.EqualValues(t, &expected, actual, "Response body should match the one from the server")
+	assert.Len(t, actual.Spec.Parameters, 2, "Parameters should be decoded")
+	assert.Equal(t, "fetch:template", actual.Spec.Steps[0].Action, "Steps should be decoded")
+	assert.Equal(t, "catalog", actual.Spec.Output.Links[1].Icon, "Output should be decoded")
+}
+
+// TestKindTemplateList tests functionality of listing templates.
+func TestKindTemplateList(t *testing.T) {
+	const dataFile = "testdata/template.json"
+
+	expected := TemplateEntityV1beta3{}
+	expectedData, _ := os.ReadFile(dataFile)
+	err := json.Unmarshal(expectedData, &expected)
+
+	assert.FileExists(t, dataFile, "Test data file should exist")
+	assert.NoError(t, err, "Unmarshal should not return an error")
+
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("filter", "kind=template,metadata.namespace=default").
+		Reply(200).
+		JSON([]json.RawMessage{expectedData})
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	s := newTemplateService(&entityService{
+		client:  c,
+		apiPath: "/catalog/entities",
+	})
+
+	actual, resp, err := s.List(context.Background(), &ListEntityOptions{
+		Filters: []string{"metadata.namespace=default"},
+	})
+	assert.NoError(t, err, "List should not return an error")
+	assert.NotEmpty(t, resp, "Response should not be empty")
+	assert.EqualValues(t, []TemplateEntityV1beta3{expected}, actual, "Response body should match the one from the server")
+}
+
+// TestTemplateParametersUnmarshalJSON tests decoding of template parameters given as a list or as a single object.
+func TestTemplateParametersUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected TemplateParameters
+	}{
+		{
+			name:     "list",
+			data:     `[{"title": "First", "required": ["name"]}, {"title": "Second"}]`,
+			expected: TemplateParameters{{Title: "First", Required: []string{"name"}}, {Title: "Second"}},
+		},
+		{
+			name: "single object",
+			data: `{"title": "Only", "properties": {"name": {"type": "string"}}}`,
+			expected: TemplateParameters{{
+				Title:      "Only",
+				Properties: map[string]map[string]interface{}{"name": {"type": "string"}},
+				single:     true,
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var actual TemplateParameters
+			err := json.Unmarshal([]byte(test.data), &actual)
+			assert.NoError(t, err, "Unmarshal should not return an error")
+			assert.Equal(t, test.expected, actual, "Parameters should match")
+		})
+	}
+}
+
+// TestTemplateEntityV1beta3_RoundTrip tests that a template converted to the typed kind and back keeps its JSON Schema keywords,
+// step keys and the shape of its parameters.
+func TestTemplateEntityV1beta3_RoundTrip(t *testing.T) {
+	const dataFile = "testdata/template_schema.json"
+
+	var entity Entity
+	expectedData, _ := os.ReadFile(dataFile)
+	err := json.Unmarshal(expectedData, &entity)
+
+	assert.FileExists(t, dataFile, "Test data file should exist")
+	assert.NoError(t, err, "Unmarshal should not return an error")
+
+	typed, err := As[TemplateEntityV1beta3](entity)
+	assert.NoError(t, err, "As should not return an error")
+	assert.Equal(t, "object", typed.Spec.Parameters[0].Extra["type"], "Unmodelled keywords should be kept")
+	assert.Equal(t, "${{ ['frontend', 'ssr'] }}", typed.Spec.Steps[1].Extra["each"], "Unmodelled step keys should be kept")
+
+	typed.Spec.Owner = "group:web"
+	actual, err := FromTyped(&typed)
+	assert.NoError(t, err, "FromTyped should not return an error")
+
+	entity.Spec["owner"] = "group:web"
+	assert.Equal(t, entity, actual, "Round-tripped template should match the original one")
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteEntities(&buf, &typed), "Writing should not return an error")
+
+	loaded, err := LoadEntities(context.Background(), &buf, nil)
+	assert.NoError(t, err, "Loading should not return an error")
+	if assert.Len(t, loaded, 1, "Template should be loaded") {
+		assert.Equal(t, entity.Spec, loaded[0].Spec, "Written template should keep its spec")
+	}
+}
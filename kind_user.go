@@ -2,6 +2,7 @@ package backstage
 
 import (
 	"context"
+	"iter"
 	"net/http"
 )
 
@@ -47,6 +48,12 @@ type UserEntityV1alpha1Spec struct {
 	MemberOf []string `json:"memberOf,omitempty" yaml:"memberOf,omitempty"`
 }
 
+// MemberOfRefs returns the parsed references to the groups the user is a member of, with kind defaulting to "Group" and namespace
+// to ns ("default", if not specified).
+func (s *UserEntityV1alpha1Spec) MemberOfRefs(ns string) ([]EntityRef, error) {
+	return parseRefs(s.MemberOf, KindGroup, ns)
+}
+
 // userService handles communication with the user methods of the Backstage Catalog API.
 type userService typedEntityService[UserEntityV1alpha1]
 
@@ -63,3 +70,21 @@ func (s *userService) Get(ctx context.Context, n string, ns string) (*UserEntity
 	cs := (typedEntityService[UserEntityV1alpha1])(*s)
 	return cs.get(ctx, KindUser, n, ns)
 }
+
+// List returns a list of user entities. It can optionally be filtered by a set of conditions and limited to a set of fields.
+func (s *userService) List(ctx context.Context, options *ListEntityOptions) ([]UserEntityV1alpha1, *http.Response, error) {
+	cs := (typedEntityService[UserEntityV1alpha1])(*s)
+	return cs.list(ctx, KindUser, options)
+}
+
+// ListAll pages through all user entities matching the options, calling fn for each page of at most pageSize entities.
+func (s *userService) ListAll(ctx context.Context, options *ListEntityOptions, pageSize int, fn func(*EntityPage[UserEntityV1alpha1]) error) error {
+	cs := (typedEntityService[UserEntityV1alpha1])(*s)
+	return cs.listAll(ctx, KindUser, options, pageSize, fn)
+}
+
+// Stream returns an iterator over all user entities matching the options, fetched in pages of at most pageSize entities.
+func (s *userService) Stream(ctx context.Context, options *ListEntityOptions, pageSize int) iter.Seq2[UserEntityV1alpha1, error] {
+	cs := (typedEntityService[UserEntityV1alpha1])(*s)
+	return cs.stream(ctx, KindUser, options, pageSize)
+}
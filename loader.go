@@ -0,0 +1,408 @@
+package backstage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Placeholders resolved by the loader, e.g. "description: {$text: ./README.md}".
+const (
+	// PlaceholderText is replaced by the content of the referenced file, as a string.
+	PlaceholderText = "$text"
+
+	// PlaceholderJSON is replaced by the content of the referenced file, parsed as JSON.
+	PlaceholderJSON = "$json"
+
+	// PlaceholderYAML is replaced by the content of the referenced file, parsed as YAML.
+	PlaceholderYAML = "$yaml"
+)
+
+// yamlErrorLine matches the line number in errors returned by the YAML decoder.
+var yamlErrorLine = regexp.MustCompile(`line (\d+)`)
+
+// LoadOptions specifies the optional parameters of LoadEntities and LoadEntitiesFile.
+type LoadOptions struct {
+	// Source is the name of the loaded file, used in errors. LoadEntitiesFile uses the path of the file by default.
+	Source string
+
+	// BaseDir is the directory relative paths of placeholders are resolved against. LoadEntitiesFile uses the directory of
+	// the file by default, LoadEntities the current working directory.
+	BaseDir string
+
+	// ReadURL reads the content of placeholders referencing URLs, e.g. "$text: https://example.com/README.md". Placeholders
+	// referencing URLs fail if it is not set.
+	ReadURL func(ctx context.Context, u *url.URL) ([]byte, error)
+}
+
+// LoadError is returned when entities cannot be loaded, with the position of the cause in the loaded file.
+type LoadError struct {
+	// Source is the name of the loaded file, if known.
+	Source string
+
+	// Line is the line of the cause, starting at 1, or 0 if unknown.
+	Line int
+
+	// Column is the column of the cause, starting at 1, or 0 if unknown.
+	Column int
+
+	// Err is the cause of the error.
+	Err error
+}
+
+// Error returns the error message.
+func (e *LoadError) Error() string {
+	var pos []string
+	if e.Source != "" {
+		pos = append(pos, e.Source)
+	}
+
+	if e.Line > 0 {
+		pos = append(pos, strconv.Itoa(e.Line))
+	}
+
+	if e.Column > 0 {
+		pos = append(pos, strconv.Itoa(e.Column))
+	}
+
+	if len(pos) == 0 {
+		return e.Err.Error()
+	}
+
+	return fmt.Sprintf("%s: %v", strings.Join(pos, ":"), e.Err)
+}
+
+// Unwrap returns the cause of the error.
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// loader loads entities from a single file.
+type loader struct {
+	ctx     context.Context
+	options LoadOptions
+}
+
+// loadedEntity is an entity together with the YAML document it was decoded from.
+type loadedEntity struct {
+	entity Entity
+	node   *yaml.Node
+}
+
+// LoadEntities reads entities from YAML documents, e.g. a catalog-info.yaml file. Placeholders ("$text", "$json" and
+// "$yaml") are replaced by the content of the referenced files or URLs, e.g.:
+//
+//	metadata:
+//	  description:
+//	    $text: ./DESCRIPTION.md
+//
+// Errors are returned as LoadError.
+func LoadEntities(ctx context.Context, r io.Reader, options *LoadOptions) ([]Entity, error) {
+	loaded, err := newLoader(ctx, options).load(r)
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]Entity, 0, len(loaded))
+	for _, l := range loaded {
+		entities = append(entities, l.entity)
+	}
+
+	return entities, nil
+}
+
+// LoadEntitiesFile reads entities from the YAML file at the path (see LoadEntities).
+func LoadEntitiesFile(ctx context.Context, path string, options *LoadOptions) ([]Entity, error) {
+	f, opts, err := openEntitiesFile(path, options)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return LoadEntities(ctx, f, opts)
+}
+
+// LoadTypedEntities reads entities from YAML documents (see LoadEntities), converted to the typed kinds registered for their
+// API versions and kinds (see Entity.Typed).
+func LoadTypedEntities(ctx context.Context, r io.Reader, options *LoadOptions) ([]TypedEntity, error) {
+	l := newLoader(ctx, options)
+	loaded, err := l.load(r)
+	if err != nil {
+		return nil, err
+	}
+
+	typed := make([]TypedEntity, 0, len(loaded))
+	for _, e := range loaded {
+		t, err := e.entity.Typed()
+		if err != nil {
+			node := e.node
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &typeErr) {
+				if n := lookupNode(e.node, typeErr.Field); n != nil {
+					node = n
+				}
+			}
+
+			return nil, l.errorAt(node, err)
+		}
+
+		typed = append(typed, t)
+	}
+
+	return typed, nil
+}
+
+// LoadTypedEntitiesFile reads entities from the YAML file at the path (see LoadTypedEntities).
+func LoadTypedEntitiesFile(ctx context.Context, path string, options *LoadOptions) ([]TypedEntity, error) {
+	f, opts, err := openEntitiesFile(path, options)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return LoadTypedEntities(ctx, f, opts)
+}
+
+// openEntitiesFile opens the file at the path, and returns the options defaulted for the file.
+func openEntitiesFile(path string, options *LoadOptions) (*os.File, *LoadOptions, error) {
+	opts := LoadOptions{}
+	if options != nil {
+		opts = *options
+	}
+
+	if opts.Source == "" {
+		opts.Source = path
+	}
+
+	if opts.BaseDir == "" {
+		opts.BaseDir = filepath.Dir(path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return f, &opts, nil
+}
+
+// newLoader returns a loader with the options.
+func newLoader(ctx context.Context, options *LoadOptions) *loader {
+	l := &loader{ctx: ctx}
+	if options != nil {
+		l.options = *options
+	}
+
+	return l
+}
+
+// load decodes the entities of all documents, skipping empty ones.
+func (l *loader) load(r io.Reader) ([]loadedEntity, error) {
+	var loaded []loadedEntity
+
+	dec := yaml.NewDecoder(r)
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, l.errorAt(nil, err)
+		}
+
+		if len(doc.Content) == 0 || doc.Content[0].Tag == "!!null" {
+			continue
+		}
+
+		root := doc.Content[0]
+		if root.Kind != yaml.MappingNode {
+			return nil, l.errorAt(root, errors.New("entity must be a mapping"))
+		}
+
+		if err := l.resolvePlaceholders(root); err != nil {
+			return nil, err
+		}
+
+		e, err := l.decode(root)
+		if err != nil {
+			return nil, err
+		}
+
+		loaded = append(loaded, loadedEntity{entity: e, node: root})
+	}
+
+	return loaded, nil
+}
+
+// decode decodes the entity from the document and checks its required fields.
+func (l *loader) decode(root *yaml.Node) (Entity, error) {
+	var e Entity
+	if err := root.Decode(&e); err != nil {
+		return e, l.errorAt(nil, err)
+	}
+
+	for _, field := range []struct {
+		path  string
+		value string
+	}{{"apiVersion", e.ApiVersion}, {"kind", e.Kind}, {"metadata.name", e.Metadata.Name}} {
+		if field.value == "" {
+			return e, l.errorAt(root, fmt.Errorf("%s is required", field.path))
+		}
+	}
+
+	if e.Spec != nil {
+		// Spec is normalized to JSON values, e.g. float64 numbers, as returned by the catalog API.
+		b, err := json.Marshal(e.Spec)
+		if err == nil {
+			e.Spec = nil
+			err = json.Unmarshal(b, &e.Spec)
+		}
+
+		if err != nil {
+			return e, l.errorAt(lookupNode(root, "spec"), err)
+		}
+	}
+
+	return e, nil
+}
+
+// resolvePlaceholders replaces the placeholders in the node and its children. A placeholder is a mapping with a single key,
+// naming the placeholder, e.g. "$text", and the reference to the file or URL as value. Mappings with other keys starting
+// with "$" are left as they are.
+func (l *loader) resolvePlaceholders(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode && len(node.Content) == 2 && strings.HasPrefix(node.Content[0].Value, "$") {
+		resolved, ok, err := l.resolvePlaceholder(node.Content[0].Value, node.Content[1])
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			setPosition(resolved, node.Line, node.Column)
+			*node = *resolved
+
+			return nil
+		}
+	}
+
+	for _, child := range node.Content {
+		if err := l.resolvePlaceholders(child); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolvePlaceholder returns the node replacing the placeholder, or false if the placeholder is unknown.
+func (l *loader) resolvePlaceholder(name string, value *yaml.Node) (*yaml.Node, bool, error) {
+	if name != PlaceholderText && name != PlaceholderJSON && name != PlaceholderYAML {
+		return nil, false, nil
+	}
+
+	if value.Kind != yaml.ScalarNode || value.Value == "" {
+		return nil, false, l.errorAt(value, fmt.Errorf("placeholder %s must reference a file or URL", name))
+	}
+
+	b, err := l.read(value.Value)
+	if err != nil {
+		return nil, false, l.errorAt(value, fmt.Errorf("placeholder %s: %w", name, err))
+	}
+
+	switch name {
+	case PlaceholderText:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: string(b)}, true, nil
+	case PlaceholderJSON:
+		if !json.Valid(b) {
+			return nil, false, l.errorAt(value, fmt.Errorf("placeholder %s: %s is not valid JSON", name, value.Value))
+		}
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, false, l.errorAt(value, fmt.Errorf("placeholder %s: %s: %w", name, value.Value, err))
+	}
+
+	if len(doc.Content) == 0 {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"}, true, nil
+	}
+
+	return doc.Content[0], true, nil
+}
+
+// read returns the content of the file or URL referenced by a placeholder. Relative paths are resolved against the base
+// directory.
+func (l *loader) read(ref string) ([]byte, error) {
+	if u, err := url.Parse(ref); err == nil && u.Scheme != "" && u.Host != "" {
+		if l.options.ReadURL == nil {
+			return nil, fmt.Errorf("reading URLs is not supported: %s", ref)
+		}
+
+		return l.options.ReadURL(l.ctx, u)
+	}
+
+	path := filepath.FromSlash(ref)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(l.options.BaseDir, path)
+	}
+
+	return os.ReadFile(path)
+}
+
+// errorAt returns the error as LoadError, positioned at the node. Without a node, the position is taken from the error of
+// the YAML decoder, if any.
+func (l *loader) errorAt(node *yaml.Node, err error) error {
+	var loadErr *LoadError
+	if errors.As(err, &loadErr) {
+		return err
+	}
+
+	loadErr = &LoadError{Source: l.options.Source, Err: err}
+	if node != nil {
+		loadErr.Line, loadErr.Column = node.Line, node.Column
+	} else if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+		loadErr.Line, _ = strconv.Atoi(m[1])
+	}
+
+	return loadErr
+}
+
+// lookupNode returns the node at the dot-separated path of mapping keys, or nil if there is none.
+func lookupNode(node *yaml.Node, path string) *yaml.Node {
+	for _, key := range strings.Split(path, ".") {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				next = node.Content[i+1]
+				break
+			}
+		}
+
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+
+	return node
+}
+
+// setPosition sets the position of the node and its children, e.g. to the position of the placeholder they replace.
+func setPosition(node *yaml.Node, line int, column int) {
+	node.Line, node.Column = line, column
+	for _, child := range node.Content {
+		setPosition(child, line, column)
+	}
+}
@@ -0,0 +1,124 @@
+package backstage
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLoadEntitiesFile tests loading of multiple documents with placeholders resolved relative to the file.
+func TestLoadEntitiesFile(t *testing.T) {
+	entities, err := LoadEntitiesFile(context.Background(), "testdata/loader/catalog-info.yaml", nil)
+
+	assert.NoError(t, err, "Loading should not return an error")
+	assert.Len(t, entities, 4, "All documents should be loaded")
+
+	component := entities[0]
+	assert.Equal(t, "petstore", component.Metadata.Name, "Entities should be loaded in order")
+	assert.Equal(t, "The petstore service.\n", component.Metadata.Description, "$text placeholder should be resolved")
+	assert.Equal(t, []EntityLink{{URL: "https://petstore.example.com", Title: "Petstore"}}, component.Metadata.Links,
+		"$yaml placeholder should be resolved")
+	assert.Equal(t, []interface{}{"petstore"}, component.Spec["providesApis"], "Spec should be loaded")
+
+	assert.Equal(t, map[string]interface{}{"tables": []interface{}{"pets", "owners"}}, entities[2].Spec["schema"],
+		"$json placeholder should be resolved")
+	assert.Equal(t, float64(3), entities[3].Spec["stages"], "Spec values should be normalized to JSON values")
+}
+
+// TestLoadEntities tests loading of the sample catalog-info.yaml file from a reader.
+func TestLoadEntities(t *testing.T) {
+	const dataFile = "testdata/catalog-info.yaml"
+
+	data, _ := os.ReadFile(dataFile)
+	entities, err := LoadEntities(context.Background(), strings.NewReader("---\n"+string(data)+"\n---\n"), nil)
+
+	assert.FileExists(t, dataFile, "Test data file should exist")
+	assert.NoError(t, err, "Loading should not return an error")
+	assert.Len(t, entities, 1, "Empty documents should be skipped")
+	assert.Equal(t, KindComponent, entities[0].Kind, "Entity should be loaded")
+	assert.Equal(t, "backstage/backstage", entities[0].Metadata.Annotations[AnnotationGitHubProjectSlug], "Annotations should be loaded")
+}
+
+// TestLoadTypedEntitiesFile tests conversion of the loaded entities to the registered kinds.
+func TestLoadTypedEntitiesFile(t *testing.T) {
+	entities, err := LoadTypedEntitiesFile(context.Background(), "testdata/loader/catalog-info.yaml", nil)
+
+	assert.NoError(t, err, "Loading should not return an error")
+	assert.Len(t, entities, 4, "All documents should be loaded")
+	assert.IsType(t, &ComponentEntityV1alpha1{}, entities[0], "Component should be typed")
+	assert.IsType(t, &ResourceEntityV1alpha1{}, entities[2], "Resource should be typed")
+	assert.IsType(t, &Entity{}, entities[3], "Unregistered kinds should not be typed")
+
+	api, ok := entities[1].(*ApiEntityV1alpha1)
+	assert.True(t, ok, "API should be typed")
+	assert.Contains(t, api.Spec.Definition, "openapi: 3.0.0", "Definition should be loaded from the file")
+}
+
+// TestLoadEntities_URL tests resolving of placeholders referencing URLs.
+func TestLoadEntities_URL(t *testing.T) {
+	doc := "apiVersion: backstage.io/v1alpha1\nkind: API\nmetadata:\n  name: petstore\nspec:\n  definition:\n    $text: https://example.com/openapi.yaml\n"
+
+	var read string
+	entities, err := LoadEntities(context.Background(), strings.NewReader(doc), &LoadOptions{
+		ReadURL: func(_ context.Context, u *url.URL) ([]byte, error) {
+			read = u.String()
+			return []byte("openapi: 3.0.0"), nil
+		},
+	})
+
+	assert.NoError(t, err, "Loading should not return an error")
+	assert.Equal(t, "https://example.com/openapi.yaml", read, "URL should be read by the reader")
+	assert.Equal(t, "openapi: 3.0.0", entities[0].Spec["definition"], "Placeholder should be replaced by the content")
+}
+
+// TestLoadEntities_Errors tests positions of loading errors.
+func TestLoadEntities_Errors(t *testing.T) {
+	header := "apiVersion: backstage.io/v1alpha1\nkind: Component\n"
+
+	tests := []struct {
+		name   string
+		doc    string
+		typed  bool
+		line   int
+		column int
+		err    string
+	}{
+		{name: "syntax", doc: header + "metadata:\n  name: web: x\n", line: 4, err: "mapping values are not allowed"},
+		{name: "type", doc: header + "metadata:\n  name:\n    first: web\n", line: 5, err: "cannot unmarshal"},
+		{name: "not mapping", doc: "- web\n", line: 1, column: 1, err: "entity must be a mapping"},
+		{name: "missing name", doc: header + "metadata: {}\n", line: 1, column: 1, err: "metadata.name is required"},
+		{name: "missing file", doc: header + "metadata:\n  name: web\n  description: {$text: ./missing.md}\n", line: 5, column: 24,
+			err: "placeholder $text"},
+		{name: "URL", doc: header + "metadata:\n  name: web\n  description: {$text: 'https://example.com/README.md'}\n", line: 5,
+			column: 24, err: "reading URLs is not supported"},
+		{name: "invalid JSON", doc: header + "metadata:\n  name: web\nspec:\n  schema:\n    $json: ./openapi.yaml\n", line: 7, column: 12,
+			err: "not valid JSON"},
+		{name: "typed", doc: header + "metadata:\n  name: web\nspec:\n  type: service\n  owner:\n    name: team-a\n", typed: true, line: 8,
+			column: 5, err: "cannot convert Component entity web"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &LoadOptions{Source: "catalog-info.yaml", BaseDir: "testdata/loader"}
+
+			var err error
+			if tt.typed {
+				_, err = LoadTypedEntities(context.Background(), strings.NewReader(tt.doc), opts)
+			} else {
+				_, err = LoadEntities(context.Background(), strings.NewReader(tt.doc), opts)
+			}
+
+			var loadErr *LoadError
+			assert.True(t, errors.As(err, &loadErr), "Error should be a LoadError")
+			assert.Equal(t, "catalog-info.yaml", loadErr.Source, "Error should contain the source")
+			assert.Equal(t, tt.line, loadErr.Line, "Error should contain the line")
+			assert.Equal(t, tt.column, loadErr.Column, "Error should contain the column")
+			assert.Contains(t, err.Error(), tt.err, "Error should contain the cause")
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package backstage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// defaultCleanupConcurrency is the number of concurrent deletions used when CleanupOptions.Concurrency is not set.
+const defaultCleanupConcurrency = 4
+
+// referencingRelations contains the relation types of an entity pointing to the entities that reference it, e.g. an entity
+// that is a dependency of another one is referenced by that one's dependsOn.
+var referencingRelations = []string{
+	RelationOwnerOf,
+	RelationDependencyOf,
+	RelationApiConsumedBy,
+	RelationApiProvidedBy,
+	RelationHasPart,
+	RelationParentOf,
+	RelationHasMember,
+}
+
+// OrphanOptions specifies the optional parameters to the entityService.ListOrphans method.
+type OrphanOptions struct {
+	// Kinds limits the orphans to the given kinds.
+	Kinds []string
+
+	// Namespaces limits the orphans to the given namespaces.
+	Namespaces []string
+
+	// Owners limits the orphans to the ones owned by the given entities. Owners are entity references, defaulting to the
+	// "Group" kind.
+	Owners []string
+}
+
+// OrphanPlan lists the orphaned entities that would be deleted by entityService.CleanupOrphans.
+type OrphanPlan struct {
+	// Items contains the orphaned entities, ordered by their references.
+	Items []OrphanPlanItem `json:"items"`
+}
+
+// OrphanPlanItem is a single orphaned entity of the plan.
+type OrphanPlanItem struct {
+	// Ref is the reference to the orphaned entity.
+	Ref string `json:"ref"`
+
+	// Entity is the orphaned entity.
+	Entity Entity `json:"entity"`
+
+	// ReferencedBy contains references to the entities that still reference the orphaned entity, e.g. depend on it. Deleting
+	// the orphaned entity leaves their references dangling.
+	ReferencedBy []string `json:"referencedBy,omitempty"`
+}
+
+// CleanupOptions specifies the optional parameters to the entityService.CleanupOrphans method.
+type CleanupOptions struct {
+	// Concurrency is the maximum number of concurrent deletions. Defaults to 4.
+	Concurrency int
+
+	// SkipReferenced skips the orphaned entities that are still referenced by other entities.
+	SkipReferenced bool
+}
+
+// CleanupReport contains results of the deletion of orphaned entities.
+type CleanupReport struct {
+	// Deleted is the number of deleted entities.
+	Deleted int `json:"deleted"`
+
+	// Skipped is the number of entities that were not deleted because they are still referenced.
+	Skipped int `json:"skipped"`
+
+	// Failed is the number of entities that failed to be deleted.
+	Failed int `json:"failed"`
+
+	// Results contains the result of every entity of the plan, in the order of the plan.
+	Results []CleanupResult `json:"results"`
+}
+
+// CleanupResult is the result of the deletion of a single orphaned entity.
+type CleanupResult struct {
+	// Ref is the reference to the orphaned entity.
+	Ref string `json:"ref"`
+
+	// Deleted is true if the entity was deleted.
+	Deleted bool `json:"deleted"`
+
+	// Skipped is true if the entity was not deleted because it is still referenced.
+	Skipped bool `json:"skipped,omitempty"`
+
+	// Err is the error that prevented the deletion, if any. An entity that stopped being orphaned or changed since the plan
+	// was made fails with ErrEntityNotOrphan or EtagMismatchError respectively.
+	Err error `json:"-"`
+
+	// Error is the message of Err, for JSON encoding.
+	Error string `json:"error,omitempty"`
+}
+
+// ListOrphans returns the entities marked by the catalog as orphaned, i.e. no longer referenced by any location.
+func (s *entityService) ListOrphans(ctx context.Context, options *OrphanOptions) ([]Entity, error) {
+	opts := OrphanOptions{}
+	if options != nil {
+		opts = *options
+	}
+
+	f := Filter().Eq("metadata.annotations."+AnnotationOrphan, "true")
+	if len(opts.Kinds) > 0 {
+		f.In("kind", opts.Kinds...)
+	}
+
+	if len(opts.Namespaces) > 0 {
+		f.In("metadata.namespace", opts.Namespaces...)
+	}
+
+	if len(opts.Owners) > 0 {
+		owners := make([]string, 0, len(opts.Owners))
+		for _, o := range opts.Owners {
+			ref, err := s.client.ParseEntityRef(o, KindGroup)
+			if err != nil {
+				return nil, err
+			}
+
+			owners = append(owners, ref.String())
+		}
+
+		f.In("relations."+RelationOwnedBy, owners...)
+	}
+
+	filters, err := f.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	var orphans []Entity
+	err = s.ListAll(ctx, &ListEntityOptions{Filters: filters}, defaultPageSize, func(page *EntityPage[Entity]) error {
+		orphans = append(orphans, page.Entities...)
+		return nil
+	})
+
+	return orphans, err
+}
+
+// PlanOrphanCleanup returns a plan of the deletion of the orphaned entities matching the options, without deleting anything.
+func (s *entityService) PlanOrphanCleanup(ctx context.Context, options *OrphanOptions) (*OrphanPlan, error) {
+	orphans, err := s.ListOrphans(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewOrphanPlan(orphans), nil
+}
+
+// NewOrphanPlan returns a plan of the deletion of the orphaned entities. Entities referencing the orphaned ones are found
+// through relations of the orphaned entities.
+func NewOrphanPlan(orphans []Entity) *OrphanPlan {
+	plan := &OrphanPlan{Items: make([]OrphanPlanItem, 0, len(orphans))}
+
+	for _, e := range orphans {
+		ref := e.Ref()
+		item := OrphanPlanItem{Ref: ref.String(), Entity: e}
+
+		for _, r := range e.Relations {
+			if !slices.Contains(referencingRelations, r.Type) {
+				continue
+			}
+
+			target, err := r.Ref()
+			if err != nil {
+				continue
+			}
+
+			if t := target.String(); !slices.Contains(item.ReferencedBy, t) {
+				item.ReferencedBy = append(item.ReferencedBy, t)
+			}
+		}
+
+		slices.Sort(item.ReferencedBy)
+		plan.Items = append(plan.Items, item)
+	}
+
+	slices.SortFunc(plan.Items, func(a, b OrphanPlanItem) int {
+		return strings.Compare(a.Ref, b.Ref)
+	})
+
+	return plan
+}
+
+// CleanupOrphans deletes the entities of the plan, with bounded concurrency. Each entity is deleted only if it is still
+// orphaned and has not changed since the plan was made. Failures of individual deletions are reported in the results, rather
+// than stopping the cleanup. A nil plan is treated as an empty one.
+func (s *entityService) CleanupOrphans(ctx context.Context, plan *OrphanPlan, options *CleanupOptions) *CleanupReport {
+	if plan == nil {
+		plan = &OrphanPlan{}
+	}
+
+	opts := CleanupOptions{}
+	if options != nil {
+		opts = *options
+	}
+
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultCleanupConcurrency
+	}
+
+	report := &CleanupReport{Results: make([]CleanupResult, len(plan.Items))}
+	sem := make(chan struct{}, opts.Concurrency)
+	var wg sync.WaitGroup
+
+	for i, item := range plan.Items {
+		result := &report.Results[i]
+		result.Ref = item.Ref
+
+		if opts.SkipReferenced && len(item.ReferencedBy) > 0 {
+			result.Skipped = true
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				result.Err = ctx.Err()
+				return
+			}
+
+			result.Err = s.deleteOrphan(ctx, item)
+			result.Deleted = result.Err == nil
+		}()
+	}
+
+	wg.Wait()
+
+	for i := range report.Results {
+		r := &report.Results[i]
+		switch {
+		case r.Skipped:
+			report.Skipped++
+		case r.Deleted:
+			report.Deleted++
+		default:
+			report.Failed++
+			r.Error = r.Err.Error()
+		}
+	}
+
+	return report
+}
+
+// deleteOrphan deletes the orphaned entity of the plan, guarded by its etag.
+func (s *entityService) deleteOrphan(ctx context.Context, item OrphanPlanItem) error {
+	resp, err := s.DeleteByRef(ctx, item.Ref, &DeleteEntityOptions{Etag: item.Entity.Metadata.Etag, RequireOrphan: true})
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("deletion failed: %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package backstage
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+)
+
+// orphanEntity returns an orphaned component with the etag and relations of the given types and targets.
+func orphanEntity(name string, etag string, relations ...EntityRelation) Entity {
+	e := ownershipEntity(KindComponent, name, nil, relations...)
+	e.Metadata.Etag = etag
+	e.Metadata.Annotations = map[string]string{AnnotationOrphan: "true"}
+
+	return e
+}
+
+// TestEntityServiceListOrphans tests listing of orphaned entities.
+func TestEntityServiceListOrphans(t *testing.T) {
+	filter := "metadata.annotations.backstage.io/orphan=true,kind=Component,relations.ownedBy=group:default/team-a"
+
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("filter", "^"+regexp.QuoteMeta(filter)+"$").
+		Reply(200).
+		JSON([]Entity{orphanEntity("web", "e1")})
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	s := newEntityService(newCatalogService(c))
+
+	orphans, err := s.ListOrphans(context.Background(), &OrphanOptions{Kinds: []string{KindComponent}, Owners: []string{"team-a"}})
+
+	assert.NoError(t, err, "ListOrphans should not return an error")
+	assert.Len(t, orphans, 1, "ListOrphans should return the orphaned entities")
+	assert.True(t, orphans[0].Metadata.IsOrphan(), "Returned entity should be orphaned")
+}
+
+// TestNewOrphanPlan tests finding of entities referencing the orphaned ones.
+func TestNewOrphanPlan(t *testing.T) {
+	plan := NewOrphanPlan([]Entity{
+		orphanEntity("web", "e1",
+			EntityRelation{Type: RelationOwnedBy, TargetRef: "group:default/team-a"},
+			EntityRelation{Type: RelationDependencyOf, TargetRef: "component:default/app"},
+			EntityRelation{Type: RelationApiProvidedBy, TargetRef: "component:default/app"}),
+		orphanEntity("api", "e2", EntityRelation{Type: RelationDependsOn, TargetRef: "resource:default/db"}),
+	})
+
+	assert.Len(t, plan.Items, 2, "Plan should contain all orphaned entities")
+	assert.Equal(t, "component:default/api", plan.Items[0].Ref, "Plan should be ordered by references")
+	assert.Empty(t, plan.Items[0].ReferencedBy, "Dependencies of the orphaned entity should not reference it")
+	assert.Equal(t, []string{"component:default/app"}, plan.Items[1].ReferencedBy, "Dependents should reference the orphaned entity")
+}
+
+// TestEntityServiceCleanupOrphans tests deletion of orphaned entities of a plan.
+func TestEntityServiceCleanupOrphans(t *testing.T) {
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities/by-name/component/default/api").
+		Reply(200).
+		JSON(orphanEntity("api", "e2"))
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Delete("/catalog/entities/by-uid/Component-api").
+		Reply(http.StatusNoContent)
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities/by-name/component/default/db").
+		Reply(200).
+		JSON(orphanEntity("db", "changed"))
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	s := newEntityService(newCatalogService(c))
+
+	plan := NewOrphanPlan([]Entity{
+		orphanEntity("api", "e2"),
+		orphanEntity("db", "e3"),
+		orphanEntity("web", "e1", EntityRelation{Type: RelationDependencyOf, TargetRef: "component:default/app"}),
+	})
+
+	report := s.CleanupOrphans(context.Background(), plan, &CleanupOptions{Concurrency: 2, SkipReferenced: true})
+
+	assert.Equal(t, 1, report.Deleted, "Unchanged orphan should be deleted")
+	assert.Equal(t, 1, report.Skipped, "Referenced orphan should be skipped")
+	assert.Equal(t, 1, report.Failed, "Changed orphan should fail")
+	assert.True(t, report.Results[0].Deleted, "Results should be in the order of the plan")
+	assert.ErrorIs(t, report.Results[1].Err, ErrEtagMismatch, "Changed orphan should fail with etag mismatch")
+	assert.Equal(t, `entity etag does not match: expected "e3", got "changed"`, report.Results[1].Error, "Error message should be reported")
+	assert.True(t, report.Results[2].Skipped, "Referenced orphan should be skipped")
+	assert.True(t, gock.IsDone(), "Skipped orphan should not be requested")
+}
+
+// TestEntityServiceCleanupOrphans_NilPlan tests that a nil plan is cleaned up as an empty one.
+func TestEntityServiceCleanupOrphans_NilPlan(t *testing.T) {
+	c, _ := NewClient("https://foo:1234/api", "", nil)
+	s := newEntityService(newCatalogService(c))
+
+	report := s.CleanupOrphans(context.Background(), nil, nil)
+
+	assert.Zero(t, report.Deleted+report.Skipped+report.Failed, "Nil plan should not delete any entity")
+	assert.Empty(t, report.Results, "Nil plan should have no results")
+}
@@ -0,0 +1,410 @@
+package backstage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// OwnershipSource specifies where OwnershipResolver reads group memberships and ownership from.
+type OwnershipSource int
+
+const (
+	// OwnershipFromRelations uses the memberOf, childOf, parentOf and ownedBy relations computed by the catalog.
+	OwnershipFromRelations OwnershipSource = iota
+
+	// OwnershipFromSpec uses the spec.memberOf, spec.members, spec.parent, spec.children and spec.owner fields, as written in
+	// catalog files. A user is a member of the groups it lists in spec.memberOf and of the groups listing it in spec.members.
+	OwnershipFromSpec
+)
+
+// OwnershipOptions specifies the optional parameters of OwnershipResolver.
+type OwnershipOptions struct {
+	// Source of group memberships and ownership. Relations are used by default.
+	Source OwnershipSource
+
+	// IncludeParents adds the parent groups, transitively, to the effective groups, e.g. members of a sub-team act as owners
+	// of what their parent team owns.
+	IncludeParents bool
+
+	// IncludeChildren adds the child groups, transitively, to the effective groups, e.g. a team owns what its sub-teams own.
+	IncludeChildren bool
+}
+
+// OwnershipResolver resolves the effective groups of users and groups, and the entities they own. Results are cached, so that
+// repeated queries about the same users and groups do not hit the catalog again; use Reset to clear the cache. It is safe for
+// concurrent use.
+type OwnershipResolver struct {
+	entities *entityService
+	options  OwnershipOptions
+
+	mu     sync.Mutex
+	cache  map[string]*Entity
+	groups map[string][]EntityRef
+	owned  map[string][]Entity
+}
+
+// NewOwnershipResolver returns a new ownership resolver using the catalog, e.g.:
+//
+//	r := backstage.NewOwnershipResolver(client.Catalog, &backstage.OwnershipOptions{IncludeChildren: true})
+//	owned, err := r.Owned(ctx, backstage.EntityRef{Kind: backstage.KindGroup, Name: "payments"})
+func NewOwnershipResolver(c *catalogService, options *OwnershipOptions) *OwnershipResolver {
+	r := &OwnershipResolver{
+		entities: c.Entities,
+	}
+
+	if options != nil {
+		r.options = *options
+	}
+	r.Reset()
+
+	return r
+}
+
+// Reset clears the cached entities and results.
+func (r *OwnershipResolver) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cache = map[string]*Entity{}
+	r.groups = map[string][]EntityRef{}
+	r.owned = map[string][]Entity{}
+}
+
+// Groups returns the effective groups of the referenced user or group. For a user, these are the groups it is a direct member
+// of; for a group, the group itself. Depending on the options, parent and child groups are added transitively. Groups that are
+// referenced, but missing from the catalog, are included without being expanded further.
+func (r *OwnershipResolver) Groups(ctx context.Context, ref EntityRef) ([]EntityRef, error) {
+	k := refKey(ref)
+
+	r.mu.Lock()
+	cached, ok := r.groups[k]
+	r.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	var direct []EntityRef
+	switch {
+	case strings.EqualFold(ref.Kind, KindGroup):
+		direct = []EntityRef{ref}
+	case strings.EqualFold(ref.Kind, KindUser):
+		user, err := r.entity(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+
+		if direct, err = r.memberOf(ctx, user); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("ownership can only be resolved for users and groups: %s", ref)
+	}
+
+	groups, err := r.expand(ctx, direct)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.groups[k] = groups
+	r.mu.Unlock()
+
+	return groups, nil
+}
+
+// Owned returns the entities owned by the referenced user or group, or by any of its effective groups (see Groups).
+func (r *OwnershipResolver) Owned(ctx context.Context, ref EntityRef) ([]Entity, error) {
+	k := refKey(ref)
+
+	r.mu.Lock()
+	cached, ok := r.owned[k]
+	r.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	groups, err := r.Groups(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	owners := map[string]bool{k: true}
+	var filters []FilterExpression
+	for _, o := range append([]EntityRef{ref}, groups...) {
+		owners[refKey(o)] = true
+		if r.options.Source == OwnershipFromSpec {
+			filters = append(filters, Filter().In(string(FieldSpecOwner), refForms(o, KindGroup)...))
+		} else {
+			filters = append(filters, Filter().Eq("relations."+RelationOwnedBy, refKey(o)))
+		}
+	}
+
+	built, err := Or(filters...).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	var owned []Entity
+	seen := map[string]bool{}
+	err = listAll(ctx, r.entities, &ListEntityOptions{Filters: built}, defaultPageSize, func(page *EntityPage[Entity]) error {
+		for _, e := range page.Entities {
+			if seen[e.Metadata.UID] || !r.ownedBy(&e, owners) {
+				continue
+			}
+
+			seen[e.Metadata.UID] = true
+			owned = append(owned, e)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.owned[k] = owned
+	r.mu.Unlock()
+
+	return owned, nil
+}
+
+// expand adds parent and child groups to the groups, as enabled by the options.
+func (r *OwnershipResolver) expand(ctx context.Context, direct []EntityRef) ([]EntityRef, error) {
+	var groups []EntityRef
+	seen := map[string]bool{}
+	queue := direct
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if seen[refKey(current)] {
+			continue
+		}
+		seen[refKey(current)] = true
+		groups = append(groups, current)
+
+		if !r.options.IncludeParents && !r.options.IncludeChildren {
+			continue
+		}
+
+		group, err := r.entity(ctx, current)
+		if errors.Is(err, ErrEntityNotFound) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		parents, children, err := r.hierarchy(group)
+		if err != nil {
+			return nil, err
+		}
+
+		if r.options.IncludeParents {
+			queue = append(queue, parents...)
+		}
+
+		if r.options.IncludeChildren {
+			queue = append(queue, children...)
+		}
+	}
+
+	return groups, nil
+}
+
+// memberOf returns references to the groups the user is a direct member of.
+func (r *OwnershipResolver) memberOf(ctx context.Context, user *Entity) ([]EntityRef, error) {
+	if r.options.Source == OwnershipFromRelations {
+		return relationTargets(user, RelationMemberOf)
+	}
+
+	u, err := As[UserEntityV1alpha1](*user)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []EntityRef
+	if u.Spec != nil {
+		if groups, err = u.Spec.MemberOfRefs(user.Metadata.Namespace); err != nil {
+			return nil, err
+		}
+	}
+
+	listing, err := r.listingMember(ctx, user.Ref())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, g := range listing {
+		if !slices.ContainsFunc(groups, func(ref EntityRef) bool { return refKey(ref) == refKey(g) }) {
+			groups = append(groups, g)
+		}
+	}
+
+	return groups, nil
+}
+
+// listingMember returns references to the groups listing the user in spec.members. The groups are cached, so that they are
+// not fetched again when expanded.
+func (r *OwnershipResolver) listingMember(ctx context.Context, user EntityRef) ([]EntityRef, error) {
+	filters, err := Filter().Kind(KindGroup).In(string(FieldSpecMembers), refForms(user, KindUser)...).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []EntityRef
+	err = listAll(ctx, r.entities, &ListEntityOptions{Filters: filters}, defaultPageSize, func(page *EntityPage[Entity]) error {
+		for _, e := range page.Entities {
+			if !r.hasMember(&e, user) {
+				continue
+			}
+
+			r.mu.Lock()
+			r.cache[refKey(e.Ref())] = &e
+			r.mu.Unlock()
+
+			groups = append(groups, e.Ref())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// hasMember returns true if the group lists the user in spec.members. Filters match member references loosely, so the
+// membership is checked again on the returned groups.
+func (r *OwnershipResolver) hasMember(group *Entity, user EntityRef) bool {
+	members, _ := group.Spec["members"].([]interface{})
+	for _, m := range members {
+		member, _ := m.(string)
+		ref, err := parseOptionalRef(member, KindUser, group.Metadata.Namespace)
+		if err == nil && ref != nil && refKey(*ref) == refKey(user) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hierarchy returns references to the parent and child groups of the group.
+func (r *OwnershipResolver) hierarchy(group *Entity) ([]EntityRef, []EntityRef, error) {
+	if r.options.Source == OwnershipFromRelations {
+		parents, err := relationTargets(group, RelationChildOf)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		children, err := relationTargets(group, RelationParentOf)
+
+		return parents, children, err
+	}
+
+	g, err := As[GroupEntityV1alpha1](*group)
+	if err != nil || g.Spec == nil {
+		return nil, nil, err
+	}
+
+	parent, err := g.Spec.ParentRef(group.Metadata.Namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	children, err := g.Spec.ChildrenRefs(group.Metadata.Namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if parent == nil {
+		return nil, children, nil
+	}
+
+	return []EntityRef{*parent}, children, nil
+}
+
+// ownedBy returns true if the entity is owned by any of the owners. Filters match owner references loosely, so the ownership
+// is checked again on the returned entities.
+func (r *OwnershipResolver) ownedBy(e *Entity, owners map[string]bool) bool {
+	if r.options.Source == OwnershipFromRelations {
+		refs, _ := relationTargets(e, RelationOwnedBy)
+		for _, o := range refs {
+			if owners[refKey(o)] {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	owner, _ := e.Spec["owner"].(string)
+	o, err := parseOptionalRef(owner, KindGroup, e.Metadata.Namespace)
+
+	return err == nil && o != nil && owners[refKey(*o)]
+}
+
+// entity returns the referenced entity, fetching it from the catalog unless it is cached.
+func (r *OwnershipResolver) entity(ctx context.Context, ref EntityRef) (*Entity, error) {
+	k := refKey(ref)
+
+	r.mu.Lock()
+	cached, ok := r.cache[k]
+	r.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	e, _, err := r.entities.getByRef(ctx, ref.String())
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.cache[k] = e
+	r.mu.Unlock()
+
+	return e, nil
+}
+
+// relationTargets returns references to the targets of the entity's relations of the given type.
+func relationTargets(e *Entity, relation string) ([]EntityRef, error) {
+	var refs []EntityRef
+	for _, rel := range e.Relations {
+		if rel.Type != relation {
+			continue
+		}
+
+		ref, err := rel.Ref()
+		if err != nil {
+			return nil, err
+		}
+
+		refs = append(refs, ref)
+	}
+
+	return refs, nil
+}
+
+// refForms returns the forms in which the entity can be referenced by a spec field defaulting to the kind, e.g. spec.owner:
+// the full reference, and the shorthands omitting the namespace and, for entities of the default kind, the kind.
+func refForms(ref EntityRef, defaultKind string) []string {
+	kind := strings.ToLower(ref.Kind)
+	forms := []string{refKey(ref), kind + ":" + ref.Name}
+	if kind == strings.ToLower(defaultKind) {
+		forms = append(forms, ref.Name)
+	}
+
+	return forms
+}
+
+// refKey returns the canonical, lowercase form of the reference, used to compare references.
+func refKey(ref EntityRef) string {
+	return strings.ToLower(ref.String())
+}
@@ -0,0 +1,221 @@
+package backstage
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+)
+
+// ownershipEntity returns an entity of the kind and name in the default namespace, with the given spec and relations.
+func ownershipEntity(kind string, name string, spec map[string]interface{}, relations ...EntityRelation) Entity {
+	e := Entity{
+		ApiVersion: ApiVersionV1alpha1,
+		Kind:       kind,
+		Metadata:   EntityMeta{UID: kind + "-" + name, Name: name, Namespace: DefaultNamespaceName},
+		Spec:       spec,
+		Relations:  relations,
+	}
+
+	return e
+}
+
+// TestOwnershipResolverRelations tests resolving of groups and owned entities from relations.
+func TestOwnershipResolverRelations(t *testing.T) {
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities/by-name/user/default/alice").
+		Reply(200).
+		JSON(ownershipEntity(KindUser, "alice", nil, EntityRelation{Type: RelationMemberOf, TargetRef: "group:default/payments"}))
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities/by-name/group/default/payments").
+		Reply(200).
+		JSON(ownershipEntity(KindGroup, "payments", nil,
+			EntityRelation{Type: RelationChildOf, TargetRef: "group:default/finance"},
+			EntityRelation{Type: RelationParentOf, TargetRef: "group:default/payments-eu"}))
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities/by-name/group/default/payments-eu").
+		Reply(200).
+		JSON(ownershipEntity(KindGroup, "payments-eu", nil, EntityRelation{Type: RelationChildOf, TargetRef: "group:default/payments"}))
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("filter", "relations.ownedBy=user:default/alice").
+		Reply(200).
+		JSON([]Entity{
+			ownershipEntity(KindComponent, "checkout", nil, EntityRelation{Type: RelationOwnedBy, TargetRef: "group:default/payments"}),
+			ownershipEntity(KindComponent, "ledger", nil, EntityRelation{Type: RelationOwnedBy, TargetRef: "group:default/payments-eu"}),
+			ownershipEntity(KindComponent, "laptop", nil, EntityRelation{Type: RelationOwnedBy, TargetRef: "user:default/alice"}),
+			ownershipEntity(KindComponent, "budget", nil, EntityRelation{Type: RelationOwnedBy, TargetRef: "group:default/finance"}),
+		})
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	r := NewOwnershipResolver(newCatalogService(c), &OwnershipOptions{IncludeChildren: true})
+	alice := EntityRef{Kind: KindUser, Namespace: DefaultNamespaceName, Name: "alice"}
+
+	groups, err := r.Groups(context.Background(), alice)
+	assert.NoError(t, err, "Groups should not return an error")
+	assert.Equal(t, []string{"group:default/payments", "group:default/payments-eu"}, refStrings(groups),
+		"Groups should contain direct groups and their children")
+
+	owned, err := r.Owned(context.Background(), alice)
+	assert.NoError(t, err, "Owned should not return an error")
+
+	var names []string
+	for _, e := range owned {
+		names = append(names, e.Metadata.Name)
+	}
+	assert.Equal(t, []string{"checkout", "ledger", "laptop"}, names, "Owned should return entities owned by the user and its groups")
+
+	cached, err := r.Owned(context.Background(), alice)
+	assert.NoError(t, err, "Cached Owned should not return an error")
+	assert.Equal(t, owned, cached, "Owned should return cached entities")
+	assert.True(t, gock.IsDone(), "All requests should be made exactly once")
+}
+
+// TestOwnershipResolverSpec tests resolving of groups and owned entities from spec fields.
+func TestOwnershipResolverSpec(t *testing.T) {
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities/by-name/user/default/alice").
+		Reply(200).
+		JSON(ownershipEntity(KindUser, "alice", map[string]interface{}{"memberOf": []string{"payments"}}))
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("filter", "kind=Group,spec.members=user:default/alice").
+		Reply(200).
+		JSON([]Entity{
+			ownershipEntity(KindGroup, "platform", map[string]interface{}{"type": "team", "members": []string{"alice"}}),
+			ownershipEntity(KindGroup, "security", map[string]interface{}{"type": "team", "members": []string{"user:guests/alice"}}),
+		})
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities/by-name/group/default/payments").
+		Reply(200).
+		JSON(ownershipEntity(KindGroup, "payments", map[string]interface{}{
+			"type": "team", "parent": "finance", "children": []string{"payments-eu"},
+		}))
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities/by-name/group/default/finance").
+		Reply(http.StatusNotFound).
+		JSON(map[string]interface{}{"error": map[string]string{"name": "NotFoundError"}})
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("filter", "spec.owner=user:default/alice").
+		Reply(200).
+		JSON([]Entity{
+			ownershipEntity(KindComponent, "checkout", map[string]interface{}{"owner": "payments"}),
+			ownershipEntity(KindComponent, "budget", map[string]interface{}{"owner": "group:default/finance"}),
+			ownershipEntity(KindComponent, "laptop", map[string]interface{}{"owner": "user:alice"}),
+			ownershipEntity(KindComponent, "ledger", map[string]interface{}{"owner": "payments-eu"}),
+		})
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	r := NewOwnershipResolver(newCatalogService(c), &OwnershipOptions{Source: OwnershipFromSpec, IncludeParents: true})
+	alice := EntityRef{Kind: KindUser, Namespace: DefaultNamespaceName, Name: "alice"}
+
+	groups, err := r.Groups(context.Background(), alice)
+	assert.NoError(t, err, "Groups should not return an error")
+	assert.Equal(t, []string{"group:default/payments", "group:default/platform", "group:default/finance"}, refStrings(groups),
+		"Groups should contain direct groups, including those listing the user as member, and their parents, including missing ones")
+
+	owned, err := r.Owned(context.Background(), alice)
+	assert.NoError(t, err, "Owned should not return an error")
+
+	var names []string
+	for _, e := range owned {
+		names = append(names, e.Metadata.Name)
+	}
+	assert.Equal(t, []string{"checkout", "budget", "laptop"}, names, "Owned should return entities owned by the user and its groups")
+}
+
+// TestOwnershipResolverSourcesAgree tests that groups resolved from spec fields match those resolved from the relations the
+// catalog computes from them.
+func TestOwnershipResolverSourcesAgree(t *testing.T) {
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities/by-name/user/default/alice").
+		Times(2).
+		Reply(200).
+		JSON(ownershipEntity(KindUser, "alice", map[string]interface{}{"memberOf": []string{"payments"}},
+			EntityRelation{Type: RelationMemberOf, TargetRef: "group:default/payments"},
+			EntityRelation{Type: RelationMemberOf, TargetRef: "group:default/platform"}))
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities/by-name/group/default/payments-eu").
+		Times(2).
+		Reply(200).
+		JSON(ownershipEntity(KindGroup, "payments-eu", map[string]interface{}{"type": "team", "parent": "payments"},
+			EntityRelation{Type: RelationChildOf, TargetRef: "group:default/payments"}))
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities/by-name/group/default/payments").
+		Times(2).
+		Reply(200).
+		JSON(ownershipEntity(KindGroup, "payments", map[string]interface{}{"type": "team", "children": []string{"payments-eu"}},
+			EntityRelation{Type: RelationParentOf, TargetRef: "group:default/payments-eu"}))
+
+	platform := ownershipEntity(KindGroup, "platform", map[string]interface{}{"type": "team", "members": []string{"alice"}},
+		EntityRelation{Type: RelationHasMember, TargetRef: "user:default/alice"})
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities/by-name/group/default/platform").
+		Reply(200).
+		JSON(platform)
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("filter", "kind=Group,spec.members=user:default/alice").
+		Reply(200).
+		JSON([]Entity{platform})
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	alice := EntityRef{Kind: KindUser, Namespace: DefaultNamespaceName, Name: "alice"}
+
+	fromRelations, err := NewOwnershipResolver(newCatalogService(c), &OwnershipOptions{IncludeChildren: true}).
+		Groups(context.Background(), alice)
+	assert.NoError(t, err, "Groups from relations should not return an error")
+
+	fromSpec, err := NewOwnershipResolver(newCatalogService(c), &OwnershipOptions{Source: OwnershipFromSpec, IncludeChildren: true}).
+		Groups(context.Background(), alice)
+	assert.NoError(t, err, "Groups from spec should not return an error")
+
+	assert.Equal(t, []string{"group:default/payments", "group:default/platform", "group:default/payments-eu"},
+		refStrings(fromRelations), "Groups from relations should contain memberships and children")
+	assert.Equal(t, refStrings(fromRelations), refStrings(fromSpec), "Groups from spec should match groups from relations")
+	assert.True(t, gock.IsDone(), "All requests should be made")
+}
+
+// TestOwnershipResolverInvalidKind tests that ownership cannot be resolved for kinds other than users and groups.
+func TestOwnershipResolverInvalidKind(t *testing.T) {
+	c, _ := NewClient("", "", nil)
+	r := NewOwnershipResolver(newCatalogService(c), nil)
+
+	_, err := r.Groups(context.Background(), EntityRef{Kind: KindComponent, Name: "web"})
+	assert.EqualError(t, err, "ownership can only be resolved for users and groups: component:default/web",
+		"Groups should return an error for components")
+}
+
+// refStrings returns canonical strings of the references.
+func refStrings(refs []EntityRef) []string {
+	var s []string
+	for _, r := range refs {
+		s = append(s, r.String())
+	}
+
+	return s
+}
@@ -0,0 +1,315 @@
+package backstage
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+// SnapshotVersion is the version of the snapshot format written by Snapshot.Write.
+const SnapshotVersion = 1
+
+// EntityReader reads entities of the catalog. It is implemented by Catalog.Entities and by Snapshot, so that code reading
+// entities can run against a live catalog as well as an offline snapshot.
+type EntityReader interface {
+	// List returns a list of entities matching the options.
+	List(ctx context.Context, options *ListEntityOptions) ([]Entity, *http.Response, error)
+
+	// Get returns an entity identified by its UID.
+	Get(ctx context.Context, uid string) (*Entity, *http.Response, error)
+}
+
+// TypedEntityReader reads entities of a single kind. It is implemented by the typed services of the catalog, e.g.
+// Catalog.Components, and by SnapshotKindReader.
+type TypedEntityReader[T any] interface {
+	// List returns a list of entities matching the options.
+	List(ctx context.Context, options *ListEntityOptions) ([]T, *http.Response, error)
+
+	// Get returns an entity identified by the name and the namespace ("default", if not specified) it belongs to.
+	Get(ctx context.Context, n string, ns string) (*T, *http.Response, error)
+}
+
+// SnapshotHeader describes the origin of a snapshot.
+type SnapshotHeader struct {
+	// Version of the snapshot format.
+	Version int `json:"version"`
+
+	// Source is the base URL of the Backstage API the snapshot was taken from.
+	Source string `json:"source"`
+
+	// CreatedAt is the time the snapshot was taken.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Entities is the number of entities in the snapshot.
+	Entities int `json:"entities"`
+
+	// Locations is the number of locations in the snapshot.
+	Locations int `json:"locations"`
+}
+
+// Snapshot is an offline copy of the catalog entities and locations. It supports reading entities like the live catalog,
+// including filters, fields and order, entirely in memory.
+type Snapshot struct {
+	// Header describes the origin of the snapshot.
+	Header SnapshotHeader
+
+	entities  []Entity
+	indexed   []*indexedEntity
+	locations []LocationResponse
+	byUID     map[string]int
+	byRef     map[string]int
+}
+
+// snapshotRecord is a single line of a snapshot file. The first line contains the header, every following line an entity
+// or a location.
+type snapshotRecord struct {
+	Header   *SnapshotHeader   `json:"header,omitempty"`
+	Entity   *Entity           `json:"entity,omitempty"`
+	Location *LocationResponse `json:"location,omitempty"`
+}
+
+// Both the catalog and snapshots implement EntityReader.
+var (
+	_ EntityReader = (*entityService)(nil)
+	_ EntityReader = (*Snapshot)(nil)
+)
+
+// Snapshot takes a snapshot of all entities and locations of the catalog.
+func (s *catalogService) Snapshot(ctx context.Context) (*Snapshot, error) {
+	var entities []Entity
+	err := s.Entities.ListAll(ctx, nil, defaultPageSize, func(page *EntityPage[Entity]) error {
+		entities = append(entities, page.Entities...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	locations, _, err := s.Locations.ListFlat(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSnapshot(s.client.BaseURL.String(), entities, locations)
+}
+
+// NewSnapshot returns a snapshot of the entities and locations, taken from the given source at the current time.
+func NewSnapshot(source string, entities []Entity, locations []LocationResponse) (*Snapshot, error) {
+	return newSnapshot(SnapshotHeader{
+		Version:   SnapshotVersion,
+		Source:    source,
+		CreatedAt: time.Now().UTC(),
+	}, entities, locations)
+}
+
+// ReadSnapshot reads a snapshot written by Snapshot.Write.
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	scanner := bufio.NewScanner(zr)
+	scanner.Buffer(nil, 64*1024*1024)
+
+	var header *SnapshotHeader
+	var entities []Entity
+	var locations []LocationResponse
+	for line := 1; scanner.Scan(); line++ {
+		var record snapshotRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("snapshot line %d: %w", line, err)
+		}
+
+		switch {
+		case line == 1 && record.Header == nil:
+			return nil, errors.New("snapshot header is missing")
+		case line == 1:
+			header = record.Header
+		case record.Entity != nil:
+			entities = append(entities, *record.Entity)
+		case record.Location != nil:
+			locations = append(locations, *record.Location)
+		default:
+			return nil, fmt.Errorf("snapshot line %d: unknown record", line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if header == nil {
+		return nil, errors.New("snapshot header is missing")
+	}
+
+	if header.Version != SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version: %d", header.Version)
+	}
+
+	return newSnapshot(*header, entities, locations)
+}
+
+// Write writes the snapshot as gzip-compressed JSON lines: the header, followed by the entities and the locations.
+func (s *Snapshot) Write(w io.Writer) error {
+	zw := gzip.NewWriter(w)
+	enc := json.NewEncoder(zw)
+
+	if err := enc.Encode(snapshotRecord{Header: &s.Header}); err != nil {
+		return err
+	}
+
+	for i := range s.entities {
+		if err := enc.Encode(snapshotRecord{Entity: &s.entities[i]}); err != nil {
+			return err
+		}
+	}
+
+	for i := range s.locations {
+		if err := enc.Encode(snapshotRecord{Location: &s.locations[i]}); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// Entities returns all entities of the snapshot.
+func (s *Snapshot) Entities() []Entity {
+	return append([]Entity(nil), s.entities...)
+}
+
+// Locations returns all locations of the snapshot.
+func (s *Snapshot) Locations() []LocationResponse {
+	return append([]LocationResponse(nil), s.locations...)
+}
+
+// List returns a list of entities. It can optionally be filtered by a set of conditions, limited to a set of fields, ordered
+// and paginated by offset and limit, with the semantics of the catalog API. Cursors are not supported. The returned response
+// is always nil.
+func (s *Snapshot) List(_ context.Context, options *ListEntityOptions) ([]Entity, *http.Response, error) {
+	entities, err := queryEntities(s.indexed, options)
+
+	return entities, nil, err
+}
+
+// Get returns an entity identified by its UID, or ErrEntityNotFound. The returned response is always nil.
+func (s *Snapshot) Get(_ context.Context, uid string) (*Entity, *http.Response, error) {
+	i, ok := s.byUID[uid]
+	if !ok {
+		return nil, nil, ErrEntityNotFound
+	}
+
+	e := s.entities[i]
+
+	return &e, nil, nil
+}
+
+// GetByName returns an entity identified by its kind, name and the namespace ("default", if not specified) it belongs to,
+// or ErrEntityNotFound. The returned response is always nil.
+func (s *Snapshot) GetByName(_ context.Context, kind string, n string, ns string) (*Entity, *http.Response, error) {
+	i, ok := s.byRef[refKey(EntityRef{Kind: kind, Namespace: ns, Name: n})]
+	if !ok {
+		return nil, nil, ErrEntityNotFound
+	}
+
+	e := s.entities[i]
+
+	return &e, nil, nil
+}
+
+// SnapshotKindReader reads entities of the typed kind T from a snapshot. It is created with SnapshotKind.
+type SnapshotKindReader[T any] struct {
+	snapshot *Snapshot
+	kind     string
+	decode   func(Entity) (T, error)
+}
+
+// SnapshotKind returns a reader of entities of the typed kind T from the snapshot. The kind must be registered with
+// RegisterKind; the built-in kinds are registered by default, e.g. backstage.SnapshotKind[ComponentEntityV1alpha1](snapshot).
+func SnapshotKind[T any, PT interface {
+	*T
+	TypedEntity
+}](s *Snapshot) (*SnapshotKindReader[T], error) {
+	r, ok := lookupType(reflect.TypeOf(PT(nil)))
+	if !ok {
+		return nil, fmt.Errorf("kind is not registered: %T", *new(T))
+	}
+
+	return &SnapshotKindReader[T]{snapshot: s, kind: r.kind, decode: As[T, PT]}, nil
+}
+
+// List returns a list of entities of the kind. It can optionally be filtered by a set of conditions, limited to a set of
+// fields, ordered and paginated by offset and limit.
+func (r *SnapshotKindReader[T]) List(ctx context.Context, options *ListEntityOptions) ([]T, *http.Response, error) {
+	entities, _, err := r.snapshot.List(ctx, withKindFilter(options, r.kind))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	typed := make([]T, 0, len(entities))
+	for _, e := range entities {
+		t, err := r.decode(e)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		typed = append(typed, t)
+	}
+
+	return typed, nil, nil
+}
+
+// Get returns an entity of the kind identified by the name and the namespace ("default", if not specified) it belongs to,
+// or ErrEntityNotFound.
+func (r *SnapshotKindReader[T]) Get(ctx context.Context, n string, ns string) (*T, *http.Response, error) {
+	e, _, err := r.snapshot.GetByName(ctx, r.kind, n, ns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t, err := r.decode(*e)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &t, nil, nil
+}
+
+// newSnapshot returns a snapshot with the header, indexing the entities.
+func newSnapshot(header SnapshotHeader, entities []Entity, locations []LocationResponse) (*Snapshot, error) {
+	header.Entities, header.Locations = len(entities), len(locations)
+
+	s := &Snapshot{
+		Header:    header,
+		entities:  entities,
+		indexed:   make([]*indexedEntity, 0, len(entities)),
+		locations: locations,
+		byUID:     map[string]int{},
+		byRef:     map[string]int{},
+	}
+
+	for i := range entities {
+		e := &entities[i]
+		indexed, err := newIndexedEntity(*e)
+		if err != nil {
+			return nil, fmt.Errorf("entity %s: %w", e.Ref(), err)
+		}
+
+		s.indexed = append(s.indexed, indexed)
+		s.byRef[refKey(e.Ref())] = i
+		if e.Metadata.UID != "" {
+			s.byUID[e.Metadata.UID] = i
+		}
+	}
+
+	return s, nil
+}
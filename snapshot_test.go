@@ -0,0 +1,188 @@
+package backstage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+)
+
+// testSnapshot returns a snapshot of the entities from the test data.
+func testSnapshot(t *testing.T) *Snapshot {
+	const dataFile = "testdata/entities.json"
+
+	var entities []Entity
+	data, _ := os.ReadFile(dataFile)
+	err := json.Unmarshal(data, &entities)
+	assert.NoError(t, err, "Unmarshal should not return an error")
+
+	s, err := NewSnapshot("https://foo:1234/api", entities, []LocationResponse{{ID: "1", Type: "url", Target: "https://example.com"}})
+	assert.NoError(t, err, "NewSnapshot should not return an error")
+
+	return s
+}
+
+// TestCatalogServiceSnapshot tests taking a snapshot of the catalog.
+func TestCatalogServiceSnapshot(t *testing.T) {
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		Reply(200).
+		File("testdata/entities.json")
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/locations").
+		Reply(200).
+		File("testdata/locations.json")
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+
+	s, err := c.Catalog.Snapshot(context.Background())
+
+	assert.NoError(t, err, "Snapshot should not return an error")
+	assert.Equal(t, baseURL.String(), s.Header.Source, "Snapshot should record its source")
+	assert.Equal(t, 10, s.Header.Entities, "Snapshot should contain all entities")
+	assert.Equal(t, len(s.Locations()), s.Header.Locations, "Snapshot should contain all locations")
+	assert.False(t, s.Header.CreatedAt.IsZero(), "Snapshot should record its time")
+}
+
+// TestSnapshotWriteRead tests writing of a snapshot and reading it back.
+func TestSnapshotWriteRead(t *testing.T) {
+	s := testSnapshot(t)
+
+	var b bytes.Buffer
+	err := s.Write(&b)
+	assert.NoError(t, err, "Write should not return an error")
+
+	read, err := ReadSnapshot(&b)
+	assert.NoError(t, err, "ReadSnapshot should not return an error")
+	assert.Equal(t, s.Header.CreatedAt.UnixNano(), read.Header.CreatedAt.UnixNano(), "Header should be read back")
+	assert.Equal(t, s.Header.Source, read.Header.Source, "Header should be read back")
+	assert.Equal(t, s.Locations(), read.Locations(), "Locations should be read back")
+	assert.Len(t, read.Entities(), len(s.Entities()), "Entities should be read back")
+
+	var invalid bytes.Buffer
+	zw := gzip.NewWriter(&invalid)
+	_, _ = zw.Write([]byte(`{"entity":{"kind":"Component"}}` + "\n"))
+	_ = zw.Close()
+
+	_, err = ReadSnapshot(&invalid)
+	assert.EqualError(t, err, "snapshot header is missing", "Snapshot without header should be rejected")
+}
+
+// TestSnapshotList tests listing of entities from a snapshot.
+func TestSnapshotList(t *testing.T) {
+	s := testSnapshot(t)
+
+	tests := []struct {
+		name     string
+		options  *ListEntityOptions
+		expected []string
+	}{
+		{
+			name:     "filter",
+			options:  &ListEntityOptions{Filters: []string{"kind=component", "KIND=api,spec.owner=GUESTS"}},
+			expected: []string{"example-website", "example-grpc-api"},
+		},
+		{
+			name:     "existence",
+			options:  &ListEntityOptions{Filters: []string{"spec.owner,kind=system"}},
+			expected: []string{"examples"},
+		},
+		{
+			name:     "annotation",
+			options:  &ListEntityOptions{Filters: []string{"kind=Group,metadata.annotations.backstage.io/managed-by-location"}},
+			expected: []string{"guests"},
+		},
+		{
+			name: "order and pagination",
+			options: &ListEntityOptions{
+				Filters: []string{"kind=location"},
+				Order:   []ListEntityOrder{{Direction: OrderDescending, Field: "metadata.name"}},
+				Offset:  1,
+				Limit:   2,
+			},
+			expected: []string{"generated-aa156660b91ff3cfa0819c08e413581e6d4e4135", "generated-1eeaf62141ef6c41d7d5ff813957a6e48a21a898"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entities, resp, err := s.List(context.Background(), test.options)
+
+			var names []string
+			for _, e := range entities {
+				names = append(names, e.Metadata.Name)
+			}
+
+			assert.NoError(t, err, "List should not return an error")
+			assert.Nil(t, resp, "List should not return a response")
+			assert.Equal(t, test.expected, names, "List should return matching entities")
+		})
+	}
+}
+
+// TestSnapshotList_Fields tests limiting of listed entities to a set of fields.
+func TestSnapshotList_Fields(t *testing.T) {
+	s := testSnapshot(t)
+
+	entities, _, err := s.List(context.Background(), &ListEntityOptions{
+		Filters: []string{"kind=component"},
+		Fields:  []string{"metadata.name", "metadata.annotations.backstage.io/managed-by-location"},
+	})
+
+	assert.NoError(t, err, "List should not return an error")
+	assert.Equal(t, "example-website", entities[0].Metadata.Name, "Selected field should be returned")
+	assert.Equal(t, map[string]string{AnnotationManagedByLocation: "file:/private/tmp/back/examples/entities.yaml"},
+		entities[0].Metadata.Annotations, "Selected annotation should be returned")
+	assert.Empty(t, entities[0].Kind, "Other fields should not be returned")
+	assert.Nil(t, entities[0].Spec, "Other fields should not be returned")
+}
+
+// TestSnapshotGet tests retrieval of entities from a snapshot.
+func TestSnapshotGet(t *testing.T) {
+	s := testSnapshot(t)
+	website := s.Entities()[0]
+
+	e, _, err := s.Get(context.Background(), website.Metadata.UID)
+	assert.NoError(t, err, "Get should not return an error")
+	assert.Equal(t, website.Metadata.Name, e.Metadata.Name, "Get should return the entity by UID")
+
+	e, _, err = s.GetByName(context.Background(), "component", "EXAMPLE-WEBSITE", "")
+	assert.NoError(t, err, "GetByName should not return an error")
+	assert.Equal(t, website.Metadata.UID, e.Metadata.UID, "GetByName should return the entity by name")
+
+	_, _, err = s.Get(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrEntityNotFound, "Get should return not found error")
+}
+
+// TestSnapshotKind tests reading of typed entities from a snapshot.
+func TestSnapshotKind(t *testing.T) {
+	s := testSnapshot(t)
+
+	r, err := SnapshotKind[ComponentEntityV1alpha1](s)
+	assert.NoError(t, err, "SnapshotKind should not return an error")
+
+	var _ TypedEntityReader[ComponentEntityV1alpha1] = r
+	var _ TypedEntityReader[ComponentEntityV1alpha1] = (*componentService)(nil)
+
+	components, _, err := r.List(context.Background(), nil)
+	assert.NoError(t, err, "List should not return an error")
+	assert.Len(t, components, 1, "List should return entities of the kind")
+	assert.Equal(t, "website", components[0].Spec.Type, "List should decode typed entities")
+
+	c, _, err := r.Get(context.Background(), "example-website", "default")
+	assert.NoError(t, err, "Get should not return an error")
+	assert.Equal(t, "guests", c.Spec.Owner, "Get should decode the typed entity")
+
+	_, _, err = r.Get(context.Background(), "example-grpc-api", "default")
+	assert.ErrorIs(t, err, ErrEntityNotFound, "Get should not return entities of other kinds")
+}
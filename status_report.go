@@ -0,0 +1,249 @@
+package backstage
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// StatusTypeCatalogProcessing is the type of status items reporting errors encountered while processing catalog files.
+const StatusTypeCatalogProcessing = "backstage.io/catalog-processing"
+
+// Status item levels, in the order of increasing severity.
+const (
+	StatusLevelInfo    = "info"
+	StatusLevelWarning = "warning"
+	StatusLevelError   = "error"
+)
+
+// StatusReportOptions specifies the optional parameters of the status report.
+type StatusReportOptions struct {
+	// Filters limit the entities scanned for status items (see ListEntityOptions.Filters). All entities are scanned by default.
+	Filters []string
+
+	// Levels limit the reported status items to the given levels. All levels are reported by default.
+	Levels []string
+
+	// Types limit the reported status items to the given types. All types are reported by default.
+	Types []string
+}
+
+// StatusReport summarizes status items of entities, grouped by the location managing the entities and by the error name.
+type StatusReport struct {
+	// Items is the total number of reported status items.
+	Items int `json:"items"`
+
+	// Entities is the number of entities with reported status items.
+	Entities int `json:"entities"`
+
+	// Locations contains the reported status items grouped by location, ordered by the location.
+	Locations []StatusReportLocation `json:"locations"`
+}
+
+// StatusReportLocation contains the reported status items of entities managed by a single location.
+type StatusReportLocation struct {
+	// Location is the value of the "backstage.io/managed-by-location" annotation, or empty if the entities do not have one.
+	Location string `json:"location"`
+
+	// Errors contains the status items grouped by error name, ordered by the name.
+	Errors []StatusReportError `json:"errors"`
+}
+
+// StatusReportError contains the reported status items with the same error name.
+type StatusReportError struct {
+	// Name of the error, e.g. "InputError", or "Unknown" if the status item has no error.
+	Name string `json:"name"`
+
+	// Level is the most severe level of the status items.
+	Level string `json:"level"`
+
+	// Items contains the status items, ordered by the entity reference.
+	Items []StatusReportItem `json:"items"`
+}
+
+// StatusReportItem is a single reported status item.
+type StatusReportItem struct {
+	// Entity is the reference to the entity with the status item.
+	Entity string `json:"entity"`
+
+	// Type of the status item.
+	Type string `json:"type"`
+
+	// Level of the status item.
+	Level string `json:"level"`
+
+	// Message describing the status, or the message of the error if the status item has no message.
+	Message string `json:"message"`
+}
+
+// statusLevels ranks the status item levels by severity.
+var statusLevels = map[string]int{
+	StatusLevelInfo:    1,
+	StatusLevelWarning: 2,
+	StatusLevelError:   3,
+}
+
+// StatusReport scans the entities matching the options and returns a report of their status items, e.g. to find catalog files
+// that fail to be processed:
+//
+//	report, err := client.Catalog.Entities.StatusReport(ctx, &backstage.StatusReportOptions{
+//	        Levels: []string{backstage.StatusLevelError},
+//	        Types:  []string{backstage.StatusTypeCatalogProcessing},
+//	})
+func (s *entityService) StatusReport(ctx context.Context, options *StatusReportOptions) (*StatusReport, error) {
+	opts := StatusReportOptions{}
+	if options != nil {
+		opts = *options
+	}
+
+	listOptions := &ListEntityOptions{
+		Filters: opts.Filters,
+		Fields:  Fields(FieldKind, FieldMetadataName, FieldMetadataNamespace, FieldMetadataAnnotations, FieldStatus),
+	}
+
+	var entities []Entity
+	err := listAll(ctx, s, listOptions, defaultPageSize, func(page *EntityPage[Entity]) error {
+		for _, e := range page.Entities {
+			if e.Status != nil && len(e.Status.Items) > 0 {
+				entities = append(entities, e)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStatusReport(entities, &opts), nil
+}
+
+// NewStatusReport returns a report of the status items of the entities. Filters of the options are not applied.
+func NewStatusReport(entities []Entity, options *StatusReportOptions) *StatusReport {
+	opts := StatusReportOptions{}
+	if options != nil {
+		opts = *options
+	}
+
+	report := &StatusReport{Locations: []StatusReportLocation{}}
+	locations := map[string]map[string]*StatusReportError{}
+
+	for i := range entities {
+		e := &entities[i]
+		if e.Status == nil {
+			continue
+		}
+
+		reported := false
+		for _, item := range e.Status.Items {
+			if len(opts.Levels) > 0 && !slices.Contains(opts.Levels, item.Level) {
+				continue
+			}
+
+			if len(opts.Types) > 0 && !slices.Contains(opts.Types, item.Type) {
+				continue
+			}
+
+			location := e.Metadata.Annotations[AnnotationManagedByLocation]
+			if locations[location] == nil {
+				locations[location] = map[string]*StatusReportError{}
+			}
+
+			name, message := "Unknown", item.Message
+			if item.Error != nil {
+				name = item.Error.Name
+				if message == "" {
+					message = item.Error.Message
+				}
+			}
+
+			group := locations[location][name]
+			if group == nil {
+				group = &StatusReportError{Name: name}
+				locations[location][name] = group
+			}
+
+			if statusLevels[item.Level] > statusLevels[group.Level] {
+				group.Level = item.Level
+			}
+
+			ref := e.Ref()
+			group.Items = append(group.Items, StatusReportItem{
+				Entity:  ref.String(),
+				Type:    item.Type,
+				Level:   item.Level,
+				Message: message,
+			})
+
+			report.Items++
+			reported = true
+		}
+
+		if reported {
+			report.Entities++
+		}
+	}
+
+	for location, errors := range locations {
+		l := StatusReportLocation{Location: location}
+		for _, group := range errors {
+			slices.SortStableFunc(group.Items, func(a, b StatusReportItem) int {
+				return strings.Compare(a.Entity, b.Entity)
+			})
+			l.Errors = append(l.Errors, *group)
+		}
+
+		slices.SortFunc(l.Errors, func(a, b StatusReportError) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		report.Locations = append(report.Locations, l)
+	}
+
+	slices.SortFunc(report.Locations, func(a, b StatusReportLocation) int {
+		return strings.Compare(a.Location, b.Location)
+	})
+
+	return report
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *StatusReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(r)
+}
+
+// WriteMarkdown writes the report as a Markdown document, with a section per location.
+func (r *StatusReport) WriteMarkdown(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "# Catalog status report")
+	fmt.Fprintln(bw)
+	fmt.Fprintf(bw, "%d status items in %d entities from %d locations.\n", r.Items, r.Entities, len(r.Locations))
+
+	for _, l := range r.Locations {
+		location := l.Location
+		if location == "" {
+			location = "(unknown location)"
+		}
+
+		fmt.Fprintln(bw)
+		fmt.Fprintf(bw, "## %s\n", location)
+
+		for _, e := range l.Errors {
+			fmt.Fprintln(bw)
+			fmt.Fprintf(bw, "### %s (%s, %d)\n", e.Name, e.Level, len(e.Items))
+			fmt.Fprintln(bw)
+
+			for _, item := range e.Items {
+				fmt.Fprintf(bw, "- `%s`: %s\n", item.Entity, strings.Join(strings.Fields(item.Message), " "))
+			}
+		}
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,129 @@
+package backstage
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+)
+
+// statusEntity returns a component managed by the location, with status items of the given levels and error names.
+func statusEntity(name string, location string, items ...string) Entity {
+	e := Entity{
+		Kind:     KindComponent,
+		Metadata: EntityMeta{Name: name, Namespace: DefaultNamespaceName},
+		Status:   &EntityStatus{},
+	}
+
+	if location != "" {
+		e.Metadata.Annotations = map[string]string{AnnotationManagedByLocation: location}
+	}
+
+	for i := 0; i < len(items); i += 2 {
+		e.Status.Items = append(e.Status.Items, EntityStatusItem{
+			Type:    StatusTypeCatalogProcessing,
+			Level:   items[i],
+			Message: "Processing of " + name + " failed",
+			Error:   &EntityStatusItemError{Name: items[i+1]},
+		})
+	}
+
+	return e
+}
+
+// TestNewStatusReport tests grouping of status items by location and error name.
+func TestNewStatusReport(t *testing.T) {
+	entities := []Entity{
+		statusEntity("web", "url:https://example.com/web.yaml", StatusLevelError, "InputError", StatusLevelWarning, "NotFoundError"),
+		statusEntity("api", "url:https://example.com/web.yaml", StatusLevelWarning, "InputError"),
+		statusEntity("db", "", StatusLevelError, "NotFoundError"),
+		statusEntity("ok", "url:https://example.com/ok.yaml"),
+	}
+
+	report := NewStatusReport(entities, nil)
+
+	assert.Equal(t, 4, report.Items, "Report should count all status items")
+	assert.Equal(t, 3, report.Entities, "Report should count entities with status items")
+	assert.Len(t, report.Locations, 2, "Report should group status items by location")
+	assert.Equal(t, "", report.Locations[0].Location, "Entities without location should be grouped together")
+
+	web := report.Locations[1]
+	assert.Equal(t, "url:https://example.com/web.yaml", web.Location, "Locations should be ordered")
+	assert.Equal(t, "InputError", web.Errors[0].Name, "Errors should be grouped by name")
+	assert.Equal(t, StatusLevelError, web.Errors[0].Level, "Error level should be the most severe level of its items")
+	assert.Equal(t, "component:default/api", web.Errors[0].Items[0].Entity, "Items should be ordered by entity")
+	assert.Len(t, web.Errors[0].Items, 2, "Items of the same error should be grouped")
+
+	errorsOnly := NewStatusReport(entities, &StatusReportOptions{Levels: []string{StatusLevelError}})
+	assert.Equal(t, 2, errorsOnly.Items, "Report should contain only items of the given levels")
+	assert.Equal(t, 2, errorsOnly.Entities, "Report should count only entities with reported items")
+}
+
+// TestStatusReportWriteMarkdown tests rendering of the report as Markdown.
+func TestStatusReportWriteMarkdown(t *testing.T) {
+	report := NewStatusReport([]Entity{
+		statusEntity("web", "url:https://example.com/web.yaml", StatusLevelError, "InputError"),
+		statusEntity("db", "", StatusLevelWarning, "NotFoundError"),
+	}, nil)
+
+	expected := "# Catalog status report\n\n" +
+		"2 status items in 2 entities from 2 locations.\n\n" +
+		"## (unknown location)\n\n" +
+		"### NotFoundError (warning, 1)\n\n" +
+		"- `component:default/db`: Processing of db failed\n\n" +
+		"## url:https://example.com/web.yaml\n\n" +
+		"### InputError (error, 1)\n\n" +
+		"- `component:default/web`: Processing of web failed\n"
+
+	var b strings.Builder
+	err := report.WriteMarkdown(&b)
+
+	assert.NoError(t, err, "WriteMarkdown should not return an error")
+	assert.Equal(t, expected, b.String(), "Markdown should contain a section per location and error")
+}
+
+// TestStatusReportWriteJSON tests rendering of the report as JSON.
+func TestStatusReportWriteJSON(t *testing.T) {
+	report := NewStatusReport([]Entity{
+		statusEntity("web", "url:https://example.com/web.yaml", StatusLevelError, "InputError"),
+	}, nil)
+
+	var b strings.Builder
+	err := report.WriteJSON(&b)
+
+	var actual StatusReport
+	jsonErr := json.Unmarshal([]byte(b.String()), &actual)
+
+	assert.NoError(t, err, "WriteJSON should not return an error")
+	assert.NoError(t, jsonErr, "Output should be valid JSON")
+	assert.Equal(t, *report, actual, "JSON should contain the whole report")
+}
+
+// TestEntityServiceStatusReport tests scanning of entities for status items.
+func TestEntityServiceStatusReport(t *testing.T) {
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("filter", "kind=component").
+		MatchParam("fields", "kind,metadata.name,metadata.namespace,metadata.annotations,status").
+		Reply(200).
+		JSON([]Entity{
+			statusEntity("web", "url:https://example.com/web.yaml", StatusLevelError, "InputError"),
+			statusEntity("ok", "url:https://example.com/ok.yaml"),
+		})
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	s := newEntityService(newCatalogService(c))
+
+	report, err := s.StatusReport(context.Background(), &StatusReportOptions{Filters: []string{"kind=component"}})
+
+	assert.NoError(t, err, "StatusReport should not return an error")
+	assert.Equal(t, 1, report.Items, "Report should contain status items of the entities")
+	assert.Equal(t, "url:https://example.com/web.yaml", report.Locations[0].Location, "Report should group items by location")
+}
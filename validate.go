@@ -0,0 +1,366 @@
+package backstage
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Length limits of the catalog model.
+const (
+	// maxNameLength is the maximum length of names, namespaces, kinds, tags and parts of label keys.
+	maxNameLength = 63
+
+	// maxDNSSubdomainLength is the maximum length of prefixes of label keys, annotation keys and API versions.
+	maxDNSSubdomainLength = 253
+)
+
+var (
+	// dnsLabelPattern matches DNS labels, e.g. namespaces. Like the catalog model, it allows repeated "-", e.g. "my--ns".
+	dnsLabelPattern = regexp.MustCompile(`^[a-z0-9]+(?:-+[a-z0-9]+)*$`)
+
+	// kindPattern matches kinds.
+	kindPattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9]*$`)
+
+	// versionPattern matches versions in API versions, e.g. "v1alpha1".
+	versionPattern = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+
+	// tagPattern matches tags.
+	tagPattern = regexp.MustCompile(`^[a-z0-9:+#]+(-[a-z0-9:+#]+)*$`)
+)
+
+// specFieldType is the type of a spec field validated by Validate.
+type specFieldType int
+
+const (
+	// specString is a string.
+	specString specFieldType = iota
+
+	// specStrings is an array of strings.
+	specStrings
+
+	// specRef is an entity reference.
+	specRef
+
+	// specRefs is an array of entity references.
+	specRefs
+
+	// specArray is an array of any values.
+	specArray
+)
+
+// specField describes a field of the spec of a kind.
+type specField struct {
+	// name of the field.
+	name string
+
+	// typ of the field.
+	typ specFieldType
+
+	// required fields must be present; required strings must also not be empty.
+	required bool
+
+	// defaultKind is the kind of entity references without a kind. If empty, references must contain the kind.
+	defaultKind string
+}
+
+// specFields contains the spec fields of the built-in kinds, keyed by the lowercase kind, as defined by their JSON schemas.
+var specFields = map[string][]specField{
+	strings.ToLower(KindAPI): {
+		{name: "type", typ: specString, required: true},
+		{name: "lifecycle", typ: specString, required: true},
+		{name: "owner", typ: specRef, required: true, defaultKind: KindGroup},
+		{name: "definition", typ: specString, required: true},
+		{name: "system", typ: specRef, defaultKind: KindSystem},
+	},
+	strings.ToLower(KindComponent): {
+		{name: "type", typ: specString, required: true},
+		{name: "lifecycle", typ: specString, required: true},
+		{name: "owner", typ: specRef, required: true, defaultKind: KindGroup},
+		{name: "subcomponentOf", typ: specRef, defaultKind: KindComponent},
+		{name: "providesApis", typ: specRefs, defaultKind: KindAPI},
+		{name: "consumesApis", typ: specRefs, defaultKind: KindAPI},
+		{name: "dependsOn", typ: specRefs},
+		{name: "system", typ: specRef, defaultKind: KindSystem},
+	},
+	strings.ToLower(KindDomain): {
+		{name: "owner", typ: specRef, required: true, defaultKind: KindGroup},
+	},
+	strings.ToLower(KindGroup): {
+		{name: "type", typ: specString, required: true},
+		{name: "parent", typ: specRef, defaultKind: KindGroup},
+		{name: "children", typ: specRefs, required: true, defaultKind: KindGroup},
+		{name: "members", typ: specRefs, defaultKind: KindUser},
+	},
+	strings.ToLower(KindLocation): {
+		{name: "type", typ: specString},
+		{name: "target", typ: specString},
+		{name: "targets", typ: specStrings},
+		{name: "presence", typ: specString},
+	},
+	strings.ToLower(KindResource): {
+		{name: "type", typ: specString, required: true},
+		{name: "owner", typ: specRef, required: true, defaultKind: KindGroup},
+		{name: "dependsOn", typ: specRefs},
+		{name: "system", typ: specRef, defaultKind: KindSystem},
+	},
+	strings.ToLower(KindSystem): {
+		{name: "owner", typ: specRef, required: true, defaultKind: KindGroup},
+		{name: "domain", typ: specRef, defaultKind: KindDomain},
+	},
+	strings.ToLower(KindTemplate): {
+		{name: "type", typ: specString, required: true},
+		{name: "owner", typ: specRef, defaultKind: KindGroup},
+		{name: "steps", typ: specArray, required: true},
+	},
+	strings.ToLower(KindUser): {
+		{name: "memberOf", typ: specRefs, defaultKind: KindGroup},
+	},
+}
+
+// ValidationError describes an invalid field of an entity.
+type ValidationError struct {
+	// Path is the JSON path of the field, e.g. "metadata.name", "spec.children" or "metadata.labels[\"example.com/team\"]".
+	Path string
+
+	// Message describes why the field is invalid.
+	Message string
+}
+
+// Error returns the error message.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// validator collects validation errors of an entity.
+type validator struct {
+	errs []error
+}
+
+// Validate validates the entity locally against the rules of the catalog model, and returns all found problems joined, each
+// as ValidationError:
+//
+//   - apiVersion, kind, metadata.name and metadata.namespace must follow the formats and length limits of the catalog.
+//   - Keys of labels and annotations must be "[<prefix>/]<name>", with a DNS subdomain as prefix; values of labels must be
+//     empty or follow the format of names.
+//   - Tags must be lowercase words separated by "-".
+//   - Spec fields required by the JSON schemas of the built-in kinds must be present, e.g. spec.children of groups, and
+//     spec.presence of locations must be "required" or "optional".
+//   - Entity references in the spec and in relations must be valid; their namespaces follow the format of
+//     metadata.namespace, i.e. must be lowercase.
+//
+// Spec rules apply to the built-in kinds of "backstage.io" API versions. Both *Entity and typed kinds, e.g.
+// *GroupEntityV1alpha1, are supported; typed kinds are validated as converted by FromTyped, i.e. as the catalog would receive
+// them.
+func Validate(t TypedEntity) error {
+	if v := reflect.ValueOf(t); !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil()) {
+		return errors.New("cannot validate a nil entity")
+	}
+
+	e, err := FromTyped(t)
+	if err != nil {
+		return err
+	}
+
+	v := &validator{}
+	v.validateEntity(e)
+
+	return errors.Join(v.errs...)
+}
+
+// validateEntity validates all fields of the entity.
+func (v *validator) validateEntity(e Entity) {
+	switch {
+	case e.ApiVersion == "":
+		v.fail(jsonPath{"apiVersion"}, "is required")
+	case !isValidPrefixed(e.ApiVersion, isDNSSubdomain, func(s string) bool {
+		return len(s) <= maxNameLength && versionPattern.MatchString(s)
+	}):
+		v.fail(jsonPath{"apiVersion"}, "must be \"[<group>/]<version>\", e.g. \"backstage.io/v1alpha1\"")
+	}
+
+	switch {
+	case e.Kind == "":
+		v.fail(jsonPath{"kind"}, "is required")
+	case len(e.Kind) > maxNameLength || !kindPattern.MatchString(e.Kind):
+		v.fail(jsonPath{"kind"}, "must start with a letter, followed by letters and digits, and be at most 63 characters long")
+	}
+
+	v.validateMeta(e.Metadata)
+
+	for i, r := range e.Relations {
+		if r.Type == "" {
+			v.fail(jsonPath{"relations", i, "type"}, "is required")
+		}
+
+		v.validateRef(jsonPath{"relations", i, "targetRef"}, r.TargetRef, "")
+	}
+
+	group, _, _ := strings.Cut(e.ApiVersion, "/")
+	if fields, ok := specFields[strings.ToLower(e.Kind)]; ok && strings.HasSuffix(group, "backstage.io") {
+		v.validateSpec(strings.ToLower(e.Kind), e.Spec, fields)
+	}
+}
+
+// validateMeta validates the metadata of an entity.
+func (v *validator) validateMeta(m EntityMeta) {
+	if m.Name == "" {
+		v.fail(jsonPath{"metadata", "name"}, "is required")
+	} else if !isKubernetesLabelValue(m.Name) {
+		v.fail(jsonPath{"metadata", "name"}, "must be at most 63 characters of letters, digits, \"-\", \"_\" and \".\", "+
+			"starting and ending with a letter or digit")
+	}
+
+	if m.Namespace != "" && !isDNSLabel(m.Namespace) {
+		v.fail(jsonPath{"metadata", "namespace"}, "must be at most 63 characters of lowercase letters, digits and \"-\", "+
+			"starting and ending with a letter or digit")
+	}
+
+	for _, k := range sortedKeys(m.Labels) {
+		if !isValidPrefixed(k, isDNSSubdomain, isKubernetesLabelValue) {
+			v.fail(jsonPath{"metadata", "labels", k}, "key must be \"[<prefix>/]<name>\"")
+		}
+
+		if val := m.Labels[k]; val != "" && !isKubernetesLabelValue(val) {
+			v.fail(jsonPath{"metadata", "labels", k}, "value must be empty or at most 63 characters of letters, digits, "+
+				"\"-\", \"_\" and \".\", starting and ending with a letter or digit")
+		}
+	}
+
+	for _, k := range sortedKeys(m.Annotations) {
+		if !isValidPrefixed(k, isDNSSubdomain, isKubernetesLabelValue) {
+			v.fail(jsonPath{"metadata", "annotations", k}, "key must be \"[<prefix>/]<name>\"")
+		}
+	}
+
+	for i, tag := range m.Tags {
+		if len(tag) > maxNameLength || !tagPattern.MatchString(tag) {
+			v.fail(jsonPath{"metadata", "tags", i}, "must be at most 63 characters of lowercase letters, digits, \":\", "+
+				"\"+\" and \"#\", separated by \"-\"")
+		}
+	}
+
+	for i, l := range m.Links {
+		if l.URL == "" {
+			v.fail(jsonPath{"metadata", "links", i, "url"}, "is required")
+		}
+	}
+}
+
+// validateSpec validates the spec of a built-in kind.
+func (v *validator) validateSpec(kind string, spec map[string]interface{}, fields []specField) {
+	if spec == nil {
+		for _, f := range fields {
+			if f.required {
+				v.fail(jsonPath{"spec"}, "is required")
+				return
+			}
+		}
+	}
+
+	for _, f := range fields {
+		path := jsonPath{"spec", f.name}
+		value, ok := spec[f.name]
+		if !ok || value == nil {
+			if f.required {
+				v.fail(path, "is required")
+			}
+
+			continue
+		}
+
+		switch f.typ {
+		case specString, specRef:
+			s, ok := value.(string)
+			switch {
+			case !ok:
+				v.fail(path, "must be a string")
+			case s == "" && f.required:
+				v.fail(path, "must not be empty")
+			case s != "" && f.typ == specRef:
+				v.validateRef(path, s, f.defaultKind)
+			}
+		case specStrings, specRefs, specArray:
+			items, ok := value.([]interface{})
+			if !ok {
+				v.fail(path, "must be an array")
+				continue
+			}
+
+			if f.typ == specArray {
+				continue
+			}
+
+			for i, item := range items {
+				s, ok := item.(string)
+				switch {
+				case !ok:
+					v.fail(append(path, i), "must be a string")
+				case f.typ == specRefs:
+					v.validateRef(append(path, i), s, f.defaultKind)
+				}
+			}
+		}
+	}
+
+	if presence, ok := spec["presence"].(string); kind == strings.ToLower(KindLocation) && ok && presence != "" &&
+		presence != LocationPresenceRequired && presence != LocationPresenceOptional {
+		v.fail(jsonPath{"spec", "presence"}, fmt.Sprintf("must be %q or %q", LocationPresenceRequired, LocationPresenceOptional))
+	}
+}
+
+// validateRef validates the syntax of the entity reference. References without a kind are invalid if there is no default
+// kind.
+func (v *validator) validateRef(path jsonPath, ref string, defaultKind string) {
+	r, err := ParseEntityRef(ref, EntityRefDefaults{Kind: defaultKind})
+	switch {
+	case err != nil:
+		v.fail(path, err.Error())
+	case len(r.Kind) > maxNameLength || !kindPattern.MatchString(r.Kind):
+		v.fail(path, fmt.Sprintf("invalid entity ref %q: invalid kind", ref))
+	case !isDNSLabel(r.Namespace):
+		v.fail(path, fmt.Sprintf("invalid entity ref %q: invalid namespace", ref))
+	case !isKubernetesLabelValue(r.Name):
+		v.fail(path, fmt.Sprintf("invalid entity ref %q: invalid name", ref))
+	}
+}
+
+// fail records a validation error of the field at the path.
+func (v *validator) fail(path jsonPath, message string) {
+	v.errs = append(v.errs, &ValidationError{Path: path.String(), Message: message})
+}
+
+// isValidPrefixed returns true if s is either a valid name, or a valid prefix and a valid name separated by "/".
+func isValidPrefixed(s string, prefix func(string) bool, name func(string) bool) bool {
+	parts := strings.Split(s, "/")
+	switch len(parts) {
+	case 1:
+		return name(parts[0])
+	case 2:
+		return prefix(parts[0]) && name(parts[1])
+	}
+
+	return false
+}
+
+// isDNSLabel returns true if s is a valid DNS label, e.g. a namespace.
+func isDNSLabel(s string) bool {
+	return len(s) <= maxNameLength && dnsLabelPattern.MatchString(s)
+}
+
+// isDNSSubdomain returns true if s is a valid DNS subdomain, i.e. DNS labels separated by ".".
+func isDNSSubdomain(s string) bool {
+	if s == "" || len(s) > maxDNSSubdomainLength {
+		return false
+	}
+
+	for _, label := range strings.Split(s, ".") {
+		if !isDNSLabel(label) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,205 @@
+package backstage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// validationPaths returns the paths of the validation errors joined in err.
+func validationPaths(err error) []string {
+	var paths []string
+
+	var joined interface{ Unwrap() []error }
+	if errors.As(err, &joined) {
+		for _, e := range joined.Unwrap() {
+			var validationErr *ValidationError
+			if errors.As(e, &validationErr) {
+				paths = append(paths, validationErr.Path)
+			}
+		}
+	}
+
+	return paths
+}
+
+// TestValidate_Valid tests that entities returned by the catalog are valid.
+func TestValidate_Valid(t *testing.T) {
+	const dataFile = "testdata/entities.json"
+
+	var entities []Entity
+	expectedData, _ := os.ReadFile(dataFile)
+	err := json.Unmarshal(expectedData, &entities)
+
+	assert.FileExists(t, dataFile, "Test data file should exist")
+	assert.NoError(t, err, "Unmarshal should not return an error")
+
+	for i := range entities {
+		assert.NoError(t, Validate(&entities[i]), "Entity %s should be valid", entities[i].Metadata.Name)
+	}
+}
+
+// TestValidate tests validation errors of invalid fields.
+func TestValidate(t *testing.T) {
+	component := func(spec map[string]interface{}) Entity {
+		s := map[string]interface{}{"type": "service", "lifecycle": "production", "owner": "team-a"}
+		for k, v := range spec {
+			s[k] = v
+		}
+
+		return Entity{ApiVersion: "backstage.io/v1alpha1", Kind: KindComponent, Metadata: EntityMeta{Name: "web"}, Spec: s}
+	}
+
+	tests := []struct {
+		name   string
+		entity func() Entity
+		paths  []string
+	}{
+		{
+			name:   "valid",
+			entity: func() Entity { return component(nil) },
+		},
+		{
+			name: "header",
+			entity: func() Entity {
+				e := component(nil)
+				e.ApiVersion, e.Kind = "backstage.io/v1/alpha", ""
+				return e
+			},
+			paths: []string{"apiVersion", "kind"},
+		},
+		{
+			name: "names",
+			entity: func() Entity {
+				e := component(nil)
+				e.Metadata.Name = "-web"
+				e.Metadata.Namespace = "Default"
+				return e
+			},
+			paths: []string{"metadata.name", "metadata.namespace"},
+		},
+		{
+			name: "repeated dashes",
+			entity: func() Entity {
+				e := component(map[string]interface{}{"owner": "group:my--ns/team-a"})
+				e.Metadata.Namespace = "my--ns"
+				return e
+			},
+		},
+		{
+			name: "long name",
+			entity: func() Entity {
+				e := component(nil)
+				e.Metadata.Name = strings.Repeat("a", 64)
+				return e
+			},
+			paths: []string{"metadata.name"},
+		},
+		{
+			name: "labels and annotations",
+			entity: func() Entity {
+				e := component(nil)
+				e.Metadata.Labels = map[string]string{"example.com/team": "", "Example.com/team": "a", "tier": "not valid"}
+				e.Metadata.Annotations = map[string]string{"backstage.io/techdocs-ref": "dir:.", "a/b/c": "x"}
+				e.Metadata.Tags = []string{"java", "Go"}
+				e.Metadata.Links = []EntityLink{{Title: "Docs"}}
+				return e
+			},
+			paths: []string{`metadata.labels["Example.com/team"]`, "metadata.labels.tier", `metadata.annotations["a/b/c"]`,
+				"metadata.tags[1]", "metadata.links[0].url"},
+		},
+		{
+			name: "required spec",
+			entity: func() Entity {
+				e := component(map[string]interface{}{"owner": ""})
+				delete(e.Spec, "lifecycle")
+				return e
+			},
+			paths: []string{"spec.lifecycle", "spec.owner"},
+		},
+		{
+			name: "missing spec",
+			entity: func() Entity {
+				e := component(nil)
+				e.Spec = nil
+				return e
+			},
+			paths: []string{"spec"},
+		},
+		{
+			name: "refs",
+			entity: func() Entity {
+				e := component(map[string]interface{}{
+					"owner":        "group:default/",
+					"providesApis": []interface{}{"petstore", "api:a:b"},
+					"dependsOn":    []interface{}{"db", 1},
+					"system":       []interface{}{"shop"},
+				})
+				e.Relations = []EntityRelation{
+					{Type: RelationOwnedBy, TargetRef: "team-a"},
+					{Type: RelationOwnedBy, TargetRef: "user:Default/bob"},
+				}
+				return e
+			},
+			paths: []string{"relations[0].targetRef", "relations[1].targetRef", "spec.owner", "spec.providesApis[1]",
+				"spec.dependsOn[0]", "spec.dependsOn[1]", "spec.system"},
+		},
+		{
+			name: "location presence",
+			entity: func() Entity {
+				return Entity{ApiVersion: "backstage.io/v1alpha1", Kind: KindLocation, Metadata: EntityMeta{Name: "repo"},
+					Spec: map[string]interface{}{"targets": []interface{}{"./a.yaml"}, "presence": "maybe"}}
+			},
+			paths: []string{"spec.presence"},
+		},
+		{
+			name: "custom kind",
+			entity: func() Entity {
+				return Entity{ApiVersion: "example.com/v1", Kind: "Group", Metadata: EntityMeta{Name: "team-a"}}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := tt.entity()
+			err := Validate(&e)
+
+			if tt.paths == nil {
+				assert.NoError(t, err, "Valid entity should not return an error")
+				return
+			}
+
+			assert.Equal(t, tt.paths, validationPaths(err), "Errors should contain the paths of the invalid fields")
+		})
+	}
+}
+
+// TestValidate_Typed tests validation of typed kinds, including fields required to be present.
+func TestValidate_Typed(t *testing.T) {
+	group := &GroupEntityV1alpha1{
+		Entity:     Entity{Metadata: EntityMeta{Name: "team-a"}},
+		ApiVersion: "backstage.io/v1alpha1",
+		Kind:       KindGroup,
+		Spec:       &GroupEntityV1alpha1Spec{Type: "team"},
+	}
+
+	err := Validate(group)
+
+	assert.Equal(t, []string{"spec.children"}, validationPaths(err), "Children should be required")
+	assert.EqualError(t, err, "spec.children: is required", "Error should contain the path and the message")
+
+	group.Spec.Children = []string{}
+	assert.NoError(t, Validate(group), "Empty children should be valid")
+}
+
+// TestValidate_Nil tests that nil entities are reported as an error.
+func TestValidate_Nil(t *testing.T) {
+	assert.Error(t, Validate(nil), "Nil entity should return an error")
+	assert.Error(t, Validate((*Entity)(nil)), "Nil entity pointer should return an error")
+	assert.Error(t, Validate((*GroupEntityV1alpha1)(nil)), "Nil typed entity should return an error")
+}
@@ -0,0 +1,316 @@
+package backstage
+
+import (
+	"context"
+	"iter"
+	"slices"
+	"strings"
+	"time"
+)
+
+// WatchEventType is the type of change reported by entityService.Watch.
+type WatchEventType string
+
+const (
+	// WatchAdded is reported for entities that appeared since the previous poll, and for all entities on the first poll.
+	WatchAdded WatchEventType = "Added"
+
+	// WatchModified is reported for entities whose etag changed since the previous poll, and for all entities on resync.
+	WatchModified WatchEventType = "Modified"
+
+	// WatchDeleted is reported for entities that disappeared since the previous poll.
+	WatchDeleted WatchEventType = "Deleted"
+)
+
+const (
+	// watchFetchBatchSize is the number of changed entities fetched by a single request.
+	watchFetchBatchSize = 50
+
+	// defaultWatchMaxBackoff is the longest wait between failed polls, used when WatchOptions.MaxBackoff is not set.
+	defaultWatchMaxBackoff = 5 * time.Minute
+
+	// defaultWatchInterval is the interval between polls, used when the interval passed to entityService.Watch is not positive.
+	defaultWatchInterval = time.Minute
+)
+
+// watchSummaryFields are the fields fetched on every poll to detect changes.
+var watchSummaryFields = Fields(FieldMetadataUID, FieldMetadataEtag, FieldKind, FieldMetadataName, FieldMetadataNamespace)
+
+// WatchOptions specifies the optional parameters to the entityService.Watch method.
+type WatchOptions struct {
+	// Filters limit the watched entities (see ListEntityOptions.Filters). All entities are watched by default.
+	Filters []string
+
+	// Fields limit the fields of the entities reported by events (see ListEntityOptions.Fields). The fields used to detect
+	// changes, e.g. "metadata.uid" and "metadata.etag", are always included. All fields are reported by default.
+	Fields []string
+
+	// Resync is the interval at which all entities are reported as modified, whether they changed or not, so that consumers can
+	// reconcile their state. Resync is disabled by default.
+	Resync time.Duration
+
+	// MaxBackoff is the longest wait between polls after consecutive errors. Defaults to 5 minutes.
+	MaxBackoff time.Duration
+
+	// PageSize is the number of entities requested per page when polling. Defaults to 100.
+	PageSize int
+}
+
+// WatchEvent is a change of a watched entity.
+type WatchEvent struct {
+	// Type of the change.
+	Type WatchEventType
+
+	// Entity is the changed entity. For deleted entities, only its kind, name, namespace, uid and etag are set.
+	Entity Entity
+
+	// Resync is true for modification events reported on resync.
+	Resync bool
+}
+
+// watcher holds the state of a watch between polls.
+type watcher struct {
+	entities *entityService
+	options  WatchOptions
+	known    map[string]Entity
+	synced   time.Time
+}
+
+// Watch polls the entities matching the options at the given interval (every minute, if not positive), and reports their
+// changes. The first poll lists the entities with all requested fields and reports them as added. Later polls detect changes
+// by comparing UIDs and etags only, so that polling stays cheap with large catalogs; only the changed entities are fetched.
+// Polling errors are reported by the iterator, and polling continues with exponential backoff. The iteration ends when the
+// context is done or the consumer stops iterating, e.g.:
+//
+//	for event, err := range client.Catalog.Entities.Watch(ctx, &backstage.WatchOptions{Filters: filters}, time.Minute) {
+//	        if err != nil {
+//	                log.Println(err)
+//	                continue
+//	        }
+//	        log.Println(event.Type, event.Entity.Metadata.Name)
+//	}
+func (s *entityService) Watch(ctx context.Context, options *WatchOptions, interval time.Duration) iter.Seq2[WatchEvent, error] {
+	w := &watcher{entities: s}
+	if options != nil {
+		w.options = *options
+	}
+
+	if w.options.MaxBackoff <= 0 {
+		w.options.MaxBackoff = defaultWatchMaxBackoff
+	}
+
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+
+	return func(yield func(WatchEvent, error) bool) {
+		w.known, w.synced = nil, time.Time{}
+		wait := interval
+
+		for {
+			events, err := w.poll(ctx)
+			switch {
+			case ctx.Err() != nil:
+				return
+			case err != nil:
+				if !yield(WatchEvent{}, err) {
+					return
+				}
+				wait = min(max(wait*2, interval), w.options.MaxBackoff)
+			default:
+				for _, e := range events {
+					if !yield(e, nil) {
+						return
+					}
+				}
+				wait = interval
+			}
+
+			t := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return
+			case <-t.C:
+			}
+		}
+	}
+}
+
+// poll lists the entities and returns their changes since the previous poll. The state is updated only if the poll succeeds.
+func (w *watcher) poll(ctx context.Context) ([]WatchEvent, error) {
+	if w.known == nil {
+		return w.list(ctx)
+	}
+
+	current := map[string]Entity{}
+	err := listAll(ctx, w.entities, &ListEntityOptions{Filters: w.options.Filters, Fields: watchSummaryFields}, w.options.PageSize,
+		func(page *EntityPage[Entity]) error {
+			for _, e := range page.Entities {
+				current[e.Metadata.UID] = e
+			}
+
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	resync := w.options.Resync > 0 && time.Since(w.synced) >= w.options.Resync
+
+	var events []WatchEvent
+	var changed []string
+	for uid, e := range current {
+		known, ok := w.known[uid]
+		switch {
+		case !ok:
+			events = append(events, WatchEvent{Type: WatchAdded})
+		case known.Metadata.Etag != e.Metadata.Etag:
+			events = append(events, WatchEvent{Type: WatchModified})
+		case resync:
+			events = append(events, WatchEvent{Type: WatchModified, Resync: true})
+		default:
+			continue
+		}
+
+		events[len(events)-1].Entity.Metadata.UID = uid
+		changed = append(changed, uid)
+	}
+
+	fetched, err := w.fetch(ctx, changed)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := events[:0]
+	for _, e := range events {
+		uid := e.Entity.Metadata.UID
+		f, ok := fetched[uid]
+		if !ok {
+			// The entity was deleted between listing and fetching, so the previous state is kept for the next poll to report it.
+			if known, ok := w.known[uid]; ok {
+				current[uid] = known
+			} else {
+				delete(current, uid)
+			}
+
+			continue
+		}
+
+		if f.Metadata.Etag != "" {
+			summary := current[uid]
+			summary.Metadata.Etag = f.Metadata.Etag
+			current[uid] = summary
+		}
+
+		e.Entity = f
+		kept = append(kept, e)
+	}
+	events = kept
+
+	for uid, e := range w.known {
+		if _, ok := current[uid]; !ok {
+			events = append(events, WatchEvent{Type: WatchDeleted, Entity: e})
+		}
+	}
+
+	sortWatchEvents(events)
+
+	w.known = current
+	if resync {
+		w.synced = time.Now()
+	}
+
+	return events, nil
+}
+
+// list lists the entities on the first poll and reports all of them as added. The listed entities are reported as they are,
+// so that they need not be fetched again; only their summaries are kept as the state.
+func (w *watcher) list(ctx context.Context) ([]WatchEvent, error) {
+	options := &ListEntityOptions{Filters: w.options.Filters, Fields: w.fields()}
+	current := map[string]Entity{}
+	var events []WatchEvent
+	err := listAll(ctx, w.entities, options, w.options.PageSize, func(page *EntityPage[Entity]) error {
+		for _, e := range page.Entities {
+			if _, ok := current[e.Metadata.UID]; ok {
+				continue
+			}
+
+			current[e.Metadata.UID] = watchSummary(e)
+			events = append(events, WatchEvent{Type: WatchAdded, Entity: e})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortWatchEvents(events)
+	w.known, w.synced = current, time.Now()
+
+	return events, nil
+}
+
+// fields returns the fields of the entities reported by events: the fields set by the options, together with the fields
+// listed by watchSummaryFields, or all fields if none are set.
+func (w *watcher) fields() []string {
+	if len(w.options.Fields) == 0 {
+		return nil
+	}
+
+	fields := append(slices.Clone(w.options.Fields), watchSummaryFields...)
+	slices.Sort(fields)
+
+	return slices.Compact(fields)
+}
+
+// watchSummary returns the entity reduced to the fields listed by watchSummaryFields.
+func watchSummary(e Entity) Entity {
+	return Entity{
+		Kind: e.Kind,
+		Metadata: EntityMeta{
+			UID:       e.Metadata.UID,
+			Etag:      e.Metadata.Etag,
+			Name:      e.Metadata.Name,
+			Namespace: e.Metadata.Namespace,
+		},
+	}
+}
+
+// sortWatchEvents orders the events by their type and by references to their entities.
+func sortWatchEvents(events []WatchEvent) {
+	slices.SortFunc(events, func(a, b WatchEvent) int {
+		if c := strings.Compare(string(a.Type), string(b.Type)); c != 0 {
+			return c
+		}
+
+		ra, rb := a.Entity.Ref(), b.Entity.Ref()
+		return strings.Compare(ra.String(), rb.String())
+	})
+}
+
+// fetch returns the entities with the given UIDs, keyed by the UID. Entities are fetched in batches, using a filter matching
+// any of the UIDs.
+func (w *watcher) fetch(ctx context.Context, uids []string) (map[string]Entity, error) {
+	fetched := map[string]Entity{}
+	slices.Sort(uids)
+
+	for batch := range slices.Chunk(uids, watchFetchBatchSize) {
+		filters, err := Filter().In(string(FieldMetadataUID), batch...).Build()
+		if err != nil {
+			return nil, err
+		}
+
+		entities, _, err := list[Entity](ctx, w.entities, &ListEntityOptions{Filters: filters, Fields: w.fields()})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range entities {
+			fetched[e.Metadata.UID] = e
+		}
+	}
+
+	return fetched, nil
+}
@@ -0,0 +1,190 @@
+package backstage
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+)
+
+// watchEntity returns a component with the uid and etag.
+func watchEntity(uid string, etag string) Entity {
+	return Entity{
+		Kind:     KindComponent,
+		Metadata: EntityMeta{UID: uid, Etag: etag, Name: "component-" + uid, Namespace: DefaultNamespaceName},
+	}
+}
+
+// TestEntityServiceWatch tests reporting of added, modified and deleted entities.
+func TestEntityServiceWatch(t *testing.T) {
+	summaryFields := "^" + regexp.QuoteMeta("metadata.uid,metadata.etag,kind,metadata.name,metadata.namespace") + "$"
+
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("filter", "^kind=component$").
+		Reply(500).
+		BodyString("not json")
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("filter", "^kind=component$").
+		Reply(200).
+		JSON([]Entity{watchEntity("a", "1"), watchEntity("b", "1")})
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("fields", summaryFields).
+		Reply(200).
+		JSON([]Entity{watchEntity("a", "2")})
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("filter", "^metadata.uid=a$").
+		Reply(200).
+		JSON([]Entity{watchEntity("a", "2")})
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	s := newEntityService(newCatalogService(c))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var errs int
+	var events []WatchEvent
+	for event, err := range s.Watch(ctx, &WatchOptions{Filters: []string{"kind=component"}}, time.Millisecond) {
+		if err != nil {
+			errs++
+			continue
+		}
+
+		if events = append(events, event); len(events) == 4 {
+			break
+		}
+	}
+
+	var actual []string
+	for _, e := range events {
+		actual = append(actual, string(e.Type)+" "+e.Entity.Metadata.UID+"@"+e.Entity.Metadata.Etag)
+	}
+
+	assert.Equal(t, 1, errs, "Polling error should be reported")
+	assert.Equal(t, []string{"Added a@1", "Added b@1", "Deleted b@1", "Modified a@2"}, actual, "Changes should be reported")
+	assert.Equal(t, "component-b", events[2].Entity.Metadata.Name, "Deleted entity should be identified")
+	assert.True(t, gock.IsDone(), "Listed and unchanged entities should not be fetched")
+}
+
+// TestEntityServiceWatch_Resync tests reporting of unchanged entities on resync.
+func TestEntityServiceWatch_Resync(t *testing.T) {
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("filter", "^metadata.uid=a$").
+		Reply(200).
+		JSON([]Entity{watchEntity("a", "1")})
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		Times(2).
+		Reply(200).
+		JSON([]Entity{watchEntity("a", "1")})
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	s := newEntityService(newCatalogService(c))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var events []WatchEvent
+	for event, err := range s.Watch(ctx, &WatchOptions{Resync: time.Nanosecond}, time.Millisecond) {
+		assert.NoError(t, err, "Watch should not return an error")
+
+		if events = append(events, event); len(events) == 2 {
+			break
+		}
+	}
+
+	assert.Equal(t, WatchAdded, events[0].Type, "Entity should be added first")
+	assert.Equal(t, WatchModified, events[1].Type, "Unchanged entity should be reported as modified on resync")
+	assert.True(t, events[1].Resync, "Event should be marked as resync")
+}
+
+// TestEntityServiceWatch_Fields tests that entities are listed and fetched with the requested fields along with the fields
+// detecting changes, even if the requested fields do not include them.
+func TestEntityServiceWatch_Fields(t *testing.T) {
+	summaryFields := "^" + regexp.QuoteMeta("metadata.uid,metadata.etag,kind,metadata.name,metadata.namespace") + "$"
+	fields := "^" + regexp.QuoteMeta("kind,metadata.etag,metadata.name,metadata.namespace,metadata.uid,spec.owner") + "$"
+
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("fields", fields).
+		Reply(200).
+		JSON([]Entity{watchEntity("a", "1"), watchEntity("b", "1")})
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("fields", summaryFields).
+		Reply(200).
+		JSON([]Entity{watchEntity("a", "2"), watchEntity("b", "1")})
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		MatchParam("filter", "^metadata.uid=a$").
+		MatchParam("fields", fields).
+		Reply(200).
+		JSON([]Entity{watchEntity("a", "2")})
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	s := newEntityService(newCatalogService(c))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var actual []string
+	for event, err := range s.Watch(ctx, &WatchOptions{Fields: []string{"spec.owner"}}, time.Millisecond) {
+		assert.NoError(t, err, "Watch should not return an error")
+
+		if actual = append(actual, string(event.Type)+" "+event.Entity.Metadata.UID+"@"+event.Entity.Metadata.Etag); len(actual) == 3 {
+			break
+		}
+	}
+
+	assert.Equal(t, []string{"Added a@1", "Added b@1", "Modified a@2"}, actual, "Changes should be reported")
+	assert.True(t, gock.IsDone(), "Changed entities should be fetched with the requested fields")
+}
+
+// TestEntityServiceWatch_Interval tests that a non-positive interval falls back to the default one.
+func TestEntityServiceWatch_Interval(t *testing.T) {
+	baseURL, _ := url.Parse("https://foo:1234/api")
+	defer gock.Off()
+	gock.New(baseURL.String()).
+		MatchHeader("Accept", "application/json").
+		Get("/catalog/entities").
+		Reply(200).
+		JSON([]Entity{watchEntity("a", "1")})
+
+	c, _ := NewClient(baseURL.String(), "", nil)
+	s := newEntityService(newCatalogService(c))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	var events int
+	for _, err := range s.Watch(ctx, nil, 0) {
+		assert.NoError(t, err, "Entities should not be polled again before the default interval")
+		events++
+	}
+
+	assert.Equal(t, 1, events, "Entities should be polled once")
+}